@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIKeyLocation is where APIKeyAuth sends the API key.
+type APIKeyLocation int
+
+const (
+	// APIKeyInHeader sends the API key as a request header.
+	APIKeyInHeader APIKeyLocation = iota
+	// APIKeyInQuery sends the API key as a query parameter.
+	APIKeyInQuery
+)
+
+// APIKeyAuth sends the API key value under name with every request, either as a header or as a query
+// parameter. A query parameter already in the request URL is kept.
+func APIKeyAuth(name, value string, in APIKeyLocation) Option {
+	return func(c *Client) {
+		if in == APIKeyInHeader {
+			c.setDefaultHeader(name, value)
+			return
+		}
+
+		query := make(url.Values, len(c.query)+1)
+		for key, values := range c.query {
+			query[key] = values
+		}
+		query.Set(name, value)
+		c.query = query
+	}
+}
+
+// applyDefaultQuery appends the client default query parameters missing from the URL of req, leaving the
+// parameters already there as they were written, e.g. for pre-signed URLs.
+func (c *Client) applyDefaultQuery(req *http.Request) {
+	if len(c.query) == 0 {
+		return
+	}
+	query := req.URL.Query()
+	missing := make(url.Values)
+	for key, values := range c.query {
+		if _, ok := query[key]; !ok {
+			missing[key] = values
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	if raw := req.URL.RawQuery; len(raw) > 0 && !strings.HasSuffix(raw, "&") {
+		req.URL.RawQuery += "&"
+	}
+	req.URL.RawQuery += missing.Encode()
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestShouldSendAPIKeyInHeader(t *testing.T) {
+	var sent *http.Request
+	c := New(APIKeyAuth("X-Api-Key", "k3y", APIKeyInHeader), WithDryRun(func(r *http.Request) {
+		sent = r
+	}))
+
+	if _, err := c.Get("http://api.example.com/users", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, sent.Header, "X-Api-Key", "k3y")
+}
+
+func TestShouldSendAPIKeyInQuery(t *testing.T) {
+	var sent *http.Request
+	c := New(APIKeyAuth("api_key", "k3y", APIKeyInQuery), WithDryRun(func(r *http.Request) {
+		sent = r
+	}))
+
+	if _, err := c.Get("http://api.example.com/users?page=2", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if query := sent.URL.Query(); query.Get("api_key") != "k3y" || query.Get("page") != "2" {
+		t.Errorf("Expected API key in query got: [%v]", sent.URL)
+	}
+
+	c.Get("http://api.example.com/users?api_key=other", JSONRequestCallback)
+	if key := sent.URL.Query().Get("api_key"); key != "other" {
+		t.Errorf("Expected API key: [%v] got: [%v]", "other", key)
+	}
+}
+
+func TestShouldKeepExistingQueryAsWritten(t *testing.T) {
+	var sent *http.Request
+	c := New(APIKeyAuth("api_key", "k3y", APIKeyInQuery), WithDryRun(func(r *http.Request) {
+		sent = r
+	}))
+
+	c.Get("http://api.example.com/files?b&a=x%20y&sig=Zm9v", JSONRequestCallback)
+	assertBody(t, sent.URL.RawQuery, "b&a=x%20y&sig=Zm9v&api_key=k3y")
+
+	c.Get("http://api.example.com/files?b&api_key=other&a=x%20y", JSONRequestCallback)
+	assertBody(t, sent.URL.RawQuery, "b&api_key=other&a=x%20y")
+}
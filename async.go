@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PostAndAwait posts body content to the given URL and, when the server accepts it as an asynchronous job
+// (202 Accepted), polls its Location (or Content-Location) every pollInterval until the job answers
+// with any other status. A Retry-After header overrides the interval; polling stops with the bound context.
+func (c *Client) PostAndAwait(url string, body io.Reader, pollInterval time.Duration, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	target, err := c.resolveBaseURL(url)
+	if err != nil {
+		return ResponseEntity{Header: make(http.Header)}, wrapRequestError(http.MethodPost, url, err)
+	}
+	re, err := c.Post(target, body, requestCallback)
+	for err == nil && re.StatusCode == http.StatusAccepted {
+		location := re.Header.Get("Location")
+		if len(location) == 0 {
+			location = re.Header.Get("Content-Location")
+		}
+		if len(location) == 0 {
+			return re, nil
+		}
+		if target, err = resolveReference(target, location); err != nil {
+			return re, wrapRequestError(http.MethodGet, location, err)
+		}
+
+		select {
+		case <-time.After(retryAfter(re.Header, pollInterval)):
+		case <-c.context().Done():
+			return re, wrapRequestError(http.MethodGet, target, c.context().Err())
+		}
+		re, err = c.Get(target, requestCallback)
+	}
+	return re, err
+}
+
+// resolveReference resolves ref, e.g. a relative Location, against base.
+func resolveReference(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// retryAfter returns the delay asked for by the Retry-After header, or fallback when there's none.
+func retryAfter(header http.Header, fallback time.Duration) time.Duration {
+	value := header.Get("Retry-After")
+	if len(value) == 0 {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+		return 0
+	}
+	return fallback
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldPostAndAwaitJob(t *testing.T) {
+	ts, polls := asyncJobTestServer(2)
+	defer ts.Close()
+
+	c := New()
+	re, err := c.PostAndAwait(ts.URL+"/jobs", strings.NewReader("{\"report\":\"sales\"}"), 10*time.Millisecond, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertBody(t, re.BodyString(), "{\"status\":\"done\"}")
+	if n := atomic.LoadInt32(polls); n != 3 {
+		t.Errorf("Expected polls: [%v] got: [%v]", 3, n)
+	}
+}
+
+func TestShouldStopAwaitingWithContext(t *testing.T) {
+	ts, _ := asyncJobTestServer(1000)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := FromContext(ctx).PostAndAwait(ts.URL+"/jobs", strings.NewReader("{}"), 10*time.Millisecond, JSONRequestCallback)
+	if err == nil {
+		t.Error("Expected context error")
+	}
+}
+
+func TestShouldParseRetryAfter(t *testing.T) {
+	if d := retryAfter(http.Header{"Retry-After": {"3"}}, time.Second); d != 3*time.Second {
+		t.Errorf("Expected delay: [%v] got: [%v]", 3*time.Second, d)
+	}
+
+	if d := retryAfter(http.Header{}, time.Second); d != time.Second {
+		t.Errorf("Expected delay: [%v] got: [%v]", time.Second, d)
+	}
+}
+
+// asyncJobTestServer accepts jobs whose status is still pending for the first pending polls.
+func asyncJobTestServer(pending int32) (*httptest.Server, *int32) {
+	polls := new(int32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs":
+			w.Header().Set("Location", "/jobs/1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Path == "/jobs/1":
+			if atomic.AddInt32(polls, 1) <= pending {
+				w.Header().Set("Location", "1")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			w.Write([]byte("{\"status\":\"done\"}"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return ts, polls
+}
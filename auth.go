@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+)
+
+// TokenProvider supplies the bearer token of each request, e.g. fetching and refreshing short-lived tokens.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc is a function supplying bearer tokens, and a TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+// Token implements TokenProvider.
+func (fn TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return fn(ctx)
+}
+
+// WithBearerToken sends token as the bearer token of every request.
+func WithBearerToken(token string) Option {
+	return WithTokenProvider(TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	}))
+}
+
+// WithTokenProvider asks p for the bearer token before every request, failing the request when p fails.
+// The token is set ahead of the request callback, which can still override the Authorization header.
+func WithTokenProvider(p TokenProvider) Option {
+	return func(c *Client) {
+		c.tokenProvider = p
+	}
+}
+
+func (c *Client) applyBearerToken(req *http.Request) error {
+	if c.tokenProvider == nil {
+		return nil
+	}
+	token, err := c.tokenProvider.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// BasicAuthCallback returns a request callback setting the basic authentication credentials. User and password
+// are sent as is, base64 encoded, so no percent-encoding is needed; as per RFC 7617 the user can't contain a colon.
+func BasicAuthCallback(user, password string) func(r *http.Request) {
+	return func(r *http.Request) {
+		r.SetBasicAuth(user, password)
+	}
+}
+
+// WithBasicAuth sends the basic authentication credentials with every request, see BasicAuthCallback.
+func WithBasicAuth(user, password string) Option {
+	return func(c *Client) {
+		c.setDefaultHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
+	}
+}
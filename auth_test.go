@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestShouldSendBearerToken(t *testing.T) {
+	var sent http.Header
+	c := New(WithBearerToken("s3cr3t"), WithDryRun(func(r *http.Request) {
+		sent = r.Header
+	}))
+
+	if _, err := c.Get("http://api.example.com/users", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, sent, "Authorization", "Bearer s3cr3t")
+}
+
+func TestShouldAskTokenProviderBeforeEachRequest(t *testing.T) {
+	var sent http.Header
+	calls := 0
+	c := New(WithTokenProvider(TokenProviderFunc(func(ctx context.Context) (string, error) {
+		calls++
+		return "token-" + strconv.Itoa(calls), nil
+	})), WithDryRun(func(r *http.Request) {
+		sent = r.Header
+	}))
+
+	c.Get("http://api.example.com/users", JSONRequestCallback)
+	c.Get("http://api.example.com/users", JSONRequestCallback)
+	assertHeader(t, sent, "Authorization", "Bearer token-2")
+}
+
+func TestShouldFailOnTokenProviderError(t *testing.T) {
+	tokenErr := errors.New("token endpoint unavailable")
+	c := New(WithTokenProvider(TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return "", tokenErr
+	})))
+
+	if _, err := c.Get("http://api.example.com/users", JSONRequestCallback); !errors.Is(err, tokenErr) {
+		t.Errorf("Expected error: [%v] got: [%v]", tokenErr, err)
+	}
+}
+
+func TestShouldSendBasicAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		w.Write([]byte(fmt.Sprintf("%v %v %v", user, password, ok)))
+	}))
+	defer ts.Close()
+
+	re, err := New().Get(ts.URL, BasicAuthCallback("jose", "p@ss:wörd"))
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "jose p@ss:wörd true")
+
+	re, err = New(WithBasicAuth("jose", "p@ss:wörd")).Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "jose p@ss:wörd true")
+}
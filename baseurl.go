@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WithBaseURL resolves request URLs without a scheme against base, joining their slashes, e.g. "/users/42"
+// against "https://api.example.com/v2" is "https://api.example.com/v2/users/42". Absolute URLs are sent as is.
+func WithBaseURL(base string) Option {
+	return func(c *Client) {
+		c.baseURL = base
+	}
+}
+
+// targetURL returns the URL a request is sent to, resolved against the base URL and then rewritten.
+func (c *Client) targetURL(target string) (string, error) {
+	target, err := c.resolveBaseURL(target)
+	if err != nil {
+		return "", err
+	}
+	return c.rewriteURL(target)
+}
+
+func (c *Client) resolveBaseURL(target string) (string, error) {
+	if len(c.baseURL) == 0 {
+		return target, nil
+	}
+	if u, err := url.Parse(target); err == nil && u.IsAbs() {
+		return target, nil
+	}
+
+	joined := joinURL(c.baseURL, target)
+	u, err := url.Parse(joined)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() || len(u.Host) == 0 {
+		return "", fmt.Errorf("rest: base URL %q is not an absolute URL", c.baseURL)
+	}
+	return joined, nil
+}
+
+func joinURL(base, path string) string {
+	switch {
+	case len(path) == 0:
+		return base
+	case strings.HasPrefix(path, "?"), strings.HasPrefix(path, "#"):
+		return base + path
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestShouldJoinBaseURL(t *testing.T) {
+	cases := map[string]string{
+		"/users/42":                      "https://api.example.com/v2/users/42",
+		"users/42":                       "https://api.example.com/v2/users/42",
+		"?page=2":                        "https://api.example.com/v2/?page=2",
+		"":                               "https://api.example.com/v2/",
+		"https://other.example.com/ping": "https://other.example.com/ping",
+	}
+	c := New(WithBaseURL("https://api.example.com/v2/"))
+	for path, expected := range cases {
+		target, err := c.resolveBaseURL(path)
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		if target != expected {
+			t.Errorf("Expected URL for %q: [%v] got: [%v]", path, expected, target)
+		}
+	}
+}
+
+func TestShouldGetRelativeToBaseURL(t *testing.T) {
+	var sent string
+	c := New(WithBaseURL("https://api.example.com/v2"), WithDryRun(func(r *http.Request) {
+		sent = r.URL.String()
+	}))
+
+	if _, err := c.Get("/users/42", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if sent != "https://api.example.com/v2/users/42" {
+		t.Errorf("Expected URL: [%v] got: [%v]", "https://api.example.com/v2/users/42", sent)
+	}
+}
+
+func TestShouldRejectInvalidBaseURL(t *testing.T) {
+	c := New(WithBaseURL("api.example.com/v2"))
+	if _, err := c.Get("/users/42", JSONRequestCallback); err == nil {
+		t.Error("Expected error for a base URL without scheme")
+	}
+}
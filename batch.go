@@ -0,0 +1,24 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// PostBatch posts items as a single JSON array to the given batch URL
+func (c *Client) PostBatch(url string, items []interface{}, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	if items == nil {
+		items = []interface{}{}
+	}
+	return c.PostEntity(url, items, JSONEncoder{}, requestCallback)
+}
+
+// SplitJSONArray splits the JSON array encoded b into its raw elements, e.g. the per-item results of a batch.
+func SplitJSONArray(b []byte) ([]json.RawMessage, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
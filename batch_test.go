@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldPostBatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var operations []struct{ ID int }
+		rBody, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(rBody, &operations); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		results := make([]interface{}, len(operations))
+		for i, operation := range operations {
+			if operation.ID < 0 {
+				results[i] = map[string]string{"error": "invalid id"}
+				continue
+			}
+			results[i] = map[string]int{"id": operation.ID, "status": http.StatusCreated}
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer ts.Close()
+
+	c := New()
+	items := []interface{}{
+		struct{ ID int }{ID: 1},
+		struct{ ID int }{ID: -1},
+		map[string]int{"id": 3},
+	}
+	re, err := c.PostBatch(ts.URL, items, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+
+	results, err := SplitJSONArray(re.Body)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if len(results) != len(items) {
+		t.Fatalf("Expected results: [%v] got: [%v]", len(items), len(results))
+	}
+
+	result := struct {
+		ID     int
+		Status int
+		Error  string
+	}{}
+	DecodeJSON(results[1], &result)
+	if result.Error != "invalid id" {
+		t.Errorf("Expected second item to fail got: [%v]", string(results[1]))
+	}
+
+	DecodeJSON(results[2], &result)
+	if result.ID != 3 || result.Status != http.StatusCreated {
+		t.Errorf("Expected third item to succeed got: [%v]", string(results[2]))
+	}
+}
+
+func TestShouldNotSplitNonArray(t *testing.T) {
+	if _, err := SplitJSONArray([]byte("{\"someProperty\":\"someValue\"}")); err == nil {
+		t.Error("Expected error splitting a JSON object")
+	}
+}
@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit of its host is open.
+var ErrCircuitOpen = errors.New("rest: circuit open")
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+type circuit struct {
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// WithCircuitBreaker opens the circuit of a host after threshold consecutive failed exchanges, i.e. transport
+// errors, retryable failures and 5xx responses, failing further requests to it with ErrCircuitOpen. Once
+// cooldown passed a single probe request is let through: a success closes the circuit, a failure opens it
+// for another cooldown. Clients derived with With share the circuits.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown, hosts: make(map[string]*circuit)}
+	}
+}
+
+func (b *circuitBreaker) allow(host string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ct, ok := b.hosts[host]
+	if !ok || ct.openedAt.IsZero() {
+		return nil
+	}
+	if ct.probing || time.Since(ct.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	ct.probing = true
+	return nil
+}
+
+func (b *circuitBreaker) record(host string, failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		delete(b.hosts, host)
+		return
+	}
+
+	ct, ok := b.hosts[host]
+	if !ok {
+		ct = &circuit{}
+		b.hosts[host] = ct
+	}
+	ct.failures++
+	ct.probing = false
+	if ct.failures >= b.threshold || !ct.openedAt.IsZero() {
+		ct.openedAt = time.Now()
+	}
+}
+
+// release lets another probe through after one ended without telling whether the host is healthy.
+func (b *circuitBreaker) release(host string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ct, ok := b.hosts[host]; ok {
+		ct.probing = false
+	}
+}
+
+// recordBreaker records the outcome of an exchange with host. A caller canceling the request, or its
+// deadline passing, says nothing about the host and is neither a failure nor a success.
+func (c *Client) recordBreaker(host string, re *ResponseEntity, err error) {
+	if isContextError(err) {
+		c.breaker.release(host)
+		return
+	}
+	c.breaker.record(host, c.breakerFailure(re, err))
+}
+
+func (c *Client) breakerFailure(re *ResponseEntity, err error) bool {
+	return err != nil || re.StatusCode >= http.StatusInternalServerError || c.classify(re, err) == ErrorClassRetryable
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldOpenCircuitAfterThreshold(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+	defer ts.Close()
+	other, _ := statusSequenceTestServer(http.StatusOK)
+	defer other.Close()
+
+	c := New(WithCircuitBreaker(2, 100*time.Millisecond))
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(ts.URL, JSONRequestCallback); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	}
+
+	if _, err := c.Get(ts.URL, JSONRequestCallback); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrCircuitOpen, err)
+	}
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 2, n)
+	}
+
+	if _, err := c.Get(other.URL, JSONRequestCallback); err != nil {
+		t.Errorf("Expected other hosts to pass got: [%v]", err)
+	}
+}
+
+func TestShouldCloseCircuitAfterSuccessfulProbe(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusInternalServerError, http.StatusOK)
+	defer ts.Close()
+
+	c := New(WithCircuitBreaker(1, 100*time.Millisecond))
+	c.Get(ts.URL, JSONRequestCallback)
+	if _, err := c.Get(ts.URL, JSONRequestCallback); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrCircuitOpen, err)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		re, err := c.Get(ts.URL, JSONRequestCallback)
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		assertStatusCode(t, re.StatusCode, http.StatusOK)
+	}
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 3, n)
+	}
+}
+
+func TestShouldReopenCircuitAfterFailedProbe(t *testing.T) {
+	b := &circuitBreaker{threshold: 1, cooldown: 50 * time.Millisecond, hosts: make(map[string]*circuit)}
+	b.record("api.example.com", true)
+
+	time.Sleep(60 * time.Millisecond)
+	if err := b.allow("api.example.com"); err != nil {
+		t.Errorf("Expected probe got: [%v]", err)
+	}
+	if err := b.allow("api.example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a single probe got: [%v]", err)
+	}
+
+	b.record("api.example.com", true)
+	if err := b.allow("api.example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrCircuitOpen, err)
+	}
+}
+
+func TestShouldNotOpenCircuitForCanceledRequests(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusOK)
+	defer ts.Close()
+
+	c := New(WithCircuitBreaker(1, time.Minute))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.GetContext(ctx, ts.URL, JSONRequestCallback); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error: [%v] got: [%v]", context.Canceled, err)
+	}
+	if _, err := c.With(WithTimeout(time.Nanosecond)).Get(ts.URL, JSONRequestCallback); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error: [%v] got: [%v]", context.DeadlineExceeded, err)
+	}
+
+	if _, err := c.Get(ts.URL, JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
@@ -0,0 +1,158 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	re      ResponseEntity
+	expires time.Time
+	vary    http.Header
+}
+
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// WithCache keeps GET responses in memory, serving them while fresh according to Cache-Control max-age
+// and revalidating them with If-None-Match/If-Modified-Since once stale. Entries are keyed on the URL and
+// the Authorization header and only reused when the request headers named in Vary match; requests sending
+// Cache-Control no-cache are always revalidated and no-store bypasses the cache.
+func WithCache() Option {
+	return func(c *Client) {
+		c.cache = &responseCache{entries: make(map[string]cacheEntry)}
+	}
+}
+
+// lookup returns a fresh cached response for req, or prepares req to revalidate a stale one.
+func (rc *responseCache) lookup(req *http.Request) (ResponseEntity, bool) {
+	if !rc.cacheable(req) {
+		return ResponseEntity{}, false
+	}
+
+	rc.mu.Lock()
+	entry, ok := rc.entries[cacheKey(req)]
+	rc.mu.Unlock()
+	if !ok || !entry.matches(req) {
+		return ResponseEntity{}, false
+	}
+
+	if time.Now().Before(entry.expires) && !hasCacheDirective(req.Header, "no-cache") {
+		return entry.re.fromCache(), true
+	}
+
+	if etag := entry.re.Header.Get("ETag"); len(etag) > 0 && len(req.Header.Get("If-None-Match")) == 0 {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := entry.re.Header.Get("Last-Modified"); len(lastModified) > 0 && len(req.Header.Get("If-Modified-Since")) == 0 {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+	return ResponseEntity{}, false
+}
+
+// update stores cacheable responses and resolves a 304 Not Modified into the cached response.
+func (rc *responseCache) update(req *http.Request, re ResponseEntity, err error) ResponseEntity {
+	if err != nil || !rc.cacheable(req) {
+		return re
+	}
+
+	key := cacheKey(req)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	switch re.StatusCode {
+	case http.StatusNotModified:
+		entry, ok := rc.entries[key]
+		if !ok || !entry.matches(req) {
+			return re
+		}
+		entry.expires = cacheExpiry(re.Header)
+		rc.entries[key] = entry
+		return entry.re.fromCache()
+	case http.StatusOK:
+		if hasCacheDirective(re.Header, "no-store") || re.Header.Get("Vary") == "*" {
+			delete(rc.entries, key)
+			return re
+		}
+		expires := cacheExpiry(re.Header)
+		if len(re.Header.Get("ETag")) == 0 && len(re.Header.Get("Last-Modified")) == 0 && !time.Now().Before(expires) {
+			return re
+		}
+		rc.entries[key] = cacheEntry{re: re, expires: expires, vary: varyHeaders(re.Header, req.Header)}
+	}
+	return re
+}
+
+// cacheable reports whether req may be answered from, or stored in, the cache.
+func (rc *responseCache) cacheable(req *http.Request) bool {
+	return rc != nil && req.Method == http.MethodGet && !hasCacheDirective(req.Header, "no-store")
+}
+
+// cacheKey identifies the cache entry for req by its URL and credentials.
+func cacheKey(req *http.Request) string {
+	return req.URL.String() + "\n" + req.Header.Get("Authorization")
+}
+
+// varyHeaders captures the request header values named in a response's Vary header.
+func varyHeaders(header, requestHeader http.Header) http.Header {
+	vary := make(http.Header)
+	for _, field := range header.Values("Vary") {
+		for _, name := range strings.Split(field, ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				vary[http.CanonicalHeaderKey(name)] = requestHeader.Values(name)
+			}
+		}
+	}
+	return vary
+}
+
+// matches reports whether req carries the same values for the headers the entry varies on.
+func (entry cacheEntry) matches(req *http.Request) bool {
+	for name, values := range entry.vary {
+		if strings.Join(values, ",") != strings.Join(req.Header.Values(name), ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// hasCacheDirective reports whether the Cache-Control header contains directive.
+func hasCacheDirective(header http.Header, directive string) bool {
+	for _, field := range header.Values("Cache-Control") {
+		for _, d := range strings.Split(field, ",") {
+			if strings.EqualFold(strings.TrimSpace(d), directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cacheExpiry returns until when a response stays fresh, per its Cache-Control max-age.
+func cacheExpiry(header http.Header) time.Time {
+	now := time.Now()
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-cache" {
+			return now
+		}
+		if strings.HasPrefix(directive, "max-age=") {
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil && seconds > 0 {
+				return now.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+	return now
+}
+
+func (re ResponseEntity) fromCache() ResponseEntity {
+	re.Header = re.Header.Clone()
+	re.FromCache = true
+	return re
+}
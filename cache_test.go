@@ -0,0 +1,161 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShouldServeFreshResponseFromCache(t *testing.T) {
+	ts, requests := cacheTestServer("max-age=60")
+	defer ts.Close()
+
+	c := New(WithCache())
+	re, err := c.Get(ts.URL, acceptJSON)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if re.FromCache {
+		t.Error("First response should not come from cache")
+	}
+
+	re, err = c.Get(ts.URL, acceptJSON)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if !re.FromCache {
+		t.Error("Second response should come from cache")
+	}
+
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}")
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldServeRevalidatedResponseFromCache(t *testing.T) {
+	ts, requests := cacheTestServer("no-cache")
+	defer ts.Close()
+
+	c := New(WithCache())
+	if re, _ := c.Get(ts.URL, JSONRequestCallback); re.FromCache {
+		t.Error("First response should not come from cache")
+	}
+
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if !re.FromCache {
+		t.Error("Revalidated response should come from cache")
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}")
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldRevalidateWhenRequestSendsNoCache(t *testing.T) {
+	ts, requests := cacheTestServer("max-age=60")
+	defer ts.Close()
+
+	c := New(WithCache())
+	c.Get(ts.URL, acceptJSON)
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if !re.FromCache {
+		t.Error("Revalidated response should come from cache")
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldNotCacheWhenRequestSendsNoStore(t *testing.T) {
+	ts, requests := cacheTestServer("max-age=60")
+	defer ts.Close()
+
+	noStore := func(r *http.Request) { r.Header.Set("Cache-Control", "no-store") }
+	c := New(WithCache())
+	c.Get(ts.URL, noStore)
+	if re, _ := c.Get(ts.URL, acceptJSON); re.FromCache {
+		t.Error("Response should not come from cache")
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldNotShareCachedResponsesAcrossCredentials(t *testing.T) {
+	ts, requests := cacheTestServer("max-age=60")
+	defer ts.Close()
+
+	c := New(WithCache())
+	c.Get(ts.URL, bearer("alice"))
+	if re, _ := c.Get(ts.URL, bearer("bob")); re.FromCache {
+		t.Error("Response fetched with other credentials should not come from cache")
+	}
+	if re, _ := c.Get(ts.URL, bearer("alice")); !re.FromCache {
+		t.Error("Response fetched with the same credentials should come from cache")
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldHonourVaryWhenServingFromCache(t *testing.T) {
+	ts, requests := cacheTestServer("max-age=60")
+	defer ts.Close()
+
+	language := func(lang string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Accept-Language", lang) }
+	}
+	c := New(WithCache())
+	c.Get(ts.URL, language("en"))
+	if re, _ := c.Get(ts.URL, language("de")); re.FromCache {
+		t.Error("Response for another Accept-Language should not come from cache")
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldNotCacheWithoutCacheOption(t *testing.T) {
+	ts, _ := cacheTestServer("max-age=60")
+	defer ts.Close()
+
+	c := New()
+	c.Get(ts.URL, JSONRequestCallback)
+	if re, _ := c.Get(ts.URL, JSONRequestCallback); re.FromCache {
+		t.Error("Response should not come from cache")
+	}
+}
+
+func cacheTestServer(cacheControl string) (*httptest.Server, *int32) {
+	requests := new(int32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Set("ETag", "\"v1\"")
+		w.Header().Set("Vary", "Accept-Language")
+		if r.Header.Get("If-None-Match") == "\"v1\"" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	}))
+	return ts, requests
+}
+
+func acceptJSON(r *http.Request) {
+	r.Header.Set("Accept", "application/json")
+}
+
+func bearer(token string) func(r *http.Request) {
+	return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+)
+
+// Codec marshals values to and unmarshals them from bodies of a media type, e.g. yaml.Marshal and
+// yaml.Unmarshal for application/yaml.
+type Codec struct {
+	Marshal   func(v interface{}) ([]byte, error)
+	Unmarshal func(b []byte, v interface{}) error
+}
+
+var codecs = struct {
+	sync.RWMutex
+	byType map[string]Codec
+}{byType: map[string]Codec{
+	"application/json": {Marshal: json.Marshal, Unmarshal: json.Unmarshal},
+	"application/xml":  {Marshal: xml.Marshal, Unmarshal: xml.Unmarshal},
+	"text/xml":         {Marshal: xml.Marshal, Unmarshal: xml.Unmarshal},
+}}
+
+// RegisterCodec registers the codec for the media type, replacing any codec registered for it before.
+// This keeps optional formats like YAML out of the package dependencies.
+func RegisterCodec(mediaType string, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+	codecs.byType[strings.ToLower(mediaType)] = codec
+}
+
+// CodecFor returns the codec registered for the media type of contentType, ignoring its parameters.
+func CodecFor(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return Codec{}, false
+	}
+	codecs.RLock()
+	defer codecs.RUnlock()
+	codec, ok := codecs.byType[mediaType]
+	return codec, ok
+}
+
+// encodeCodec returns the encoding of v in a reader, using the codec registered for the media type.
+func encodeCodec(mediaType string, v interface{}) (io.Reader, error) {
+	codec, ok := CodecFor(mediaType)
+	if !ok || codec.Marshal == nil {
+		return nil, codecError(mediaType)
+	}
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// decodeCodec decodes b into the value pointed to by v, using the codec registered for the media type.
+func decodeCodec(mediaType string, b []byte, v interface{}) error {
+	codec, ok := CodecFor(mediaType)
+	if !ok || codec.Unmarshal == nil {
+		return codecError(mediaType)
+	}
+	return codec.Unmarshal(b, v)
+}
+
+// codecError is returned when no codec is registered for a media type.
+func codecError(mediaType string) error {
+	return fmt.Errorf("rest: no codec registered for %s", mediaType)
+}
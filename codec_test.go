@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// registerTestCodec registers codec for the test only, restoring the previous registration afterwards.
+func registerTestCodec(t *testing.T, mediaType string, codec Codec) {
+	codecs.RLock()
+	previous, ok := codecs.byType[mediaType]
+	codecs.RUnlock()
+	t.Cleanup(func() {
+		codecs.Lock()
+		defer codecs.Unlock()
+		if ok {
+			codecs.byType[mediaType] = previous
+		} else {
+			delete(codecs.byType, mediaType)
+		}
+	})
+	RegisterCodec(mediaType, codec)
+}
+
+func TestShouldLookUpCodecByContentType(t *testing.T) {
+	if _, ok := CodecFor("application/json; charset=utf-8"); !ok {
+		t.Error("Expected a JSON codec")
+	}
+	if _, ok := CodecFor("Text/XML"); !ok {
+		t.Error("Expected an XML codec")
+	}
+	if _, ok := CodecFor("application/vnd.unknown"); ok {
+		t.Error("Expected no codec for an unknown media type")
+	}
+
+	registerTestCodec(t, "application/vnd.test", Codec{Marshal: json.Marshal, Unmarshal: json.Unmarshal})
+	if _, ok := CodecFor("application/vnd.test"); !ok {
+		t.Error("Expected the registered codec")
+	}
+}
@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// WithGzipRequestForHosts gzips request bodies sent to the given hosts only, as not every
+// server accepts a Content-Encoding on requests. Hosts match either "host" or "host:port".
+func WithGzipRequestForHosts(hosts ...string) Option {
+	return func(c *Client) {
+		allowed := make(map[string]bool, len(c.gzipRequestHosts)+len(hosts))
+		for host := range c.gzipRequestHosts {
+			allowed[host] = true
+		}
+		for _, host := range hosts {
+			allowed[host] = true
+		}
+		c.gzipRequestHosts = allowed
+	}
+}
+
+func (c *Client) compressRequest(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if !c.gzipRequestHosts[req.URL.Host] && !c.gzipRequestHosts[req.URL.Hostname()] {
+		return nil
+	}
+
+	defer req.Body.Close()
+	w := new(bytes.Buffer)
+	zw := gzip.NewWriter(w)
+	if _, err := io.Copy(zw, req.Body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	compressed := w.Bytes()
+	req.Body = ioutil.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.ContentLength = int64(len(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// PostJSONGzip posts the gzipped JSON encoding of v to the given URL, asking for a gzipped reply as well.
+// Gzipped replies are decompressed before the ResponseEntity is returned.
+func (c *Client) PostJSONGzip(url string, v interface{}, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+
+	w := new(bytes.Buffer)
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b); err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+	if err := zw.Close(); err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+
+	// The body is already gzipped, so it must not be compressed again for WithGzipRequestForHosts hosts.
+	cc := c.With(func(cc *Client) {
+		cc.gzipRequestHosts = nil
+	})
+	return cc.Post(url, bytes.NewReader(w.Bytes()), func(r *http.Request) {
+		if requestCallback != nil {
+			requestCallback(r)
+		}
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Content-Encoding", "gzip")
+		r.Header.Set("Accept-Encoding", "gzip")
+	})
+}
+
+// decompressResponse decompresses gzip encoded bodies the transport left as is, which it does whenever
+// the request set its own Accept-Encoding.
+func decompressResponse(re *ResponseEntity) error {
+	if !strings.EqualFold(re.Header.Get("Content-Encoding"), "gzip") || len(re.Body) == 0 {
+		return nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(re.Body))
+	if err != nil {
+		return err
+	}
+	body, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+	re.Body = body
+	re.Header.Del("Content-Encoding")
+	re.Header.Del("Content-Length")
+	return nil
+}
+
+// WithSniffCompression decompresses response bodies by their leading magic bytes (gzip and zlib), for servers
+// sending compressed bodies without a correct Content-Encoding header. Bodies that fail to decompress are
+// kept as received.
+func WithSniffCompression() Option {
+	return func(c *Client) {
+		c.sniffCompression = true
+	}
+}
+
+func sniffDecompress(re *ResponseEntity) {
+	var r io.Reader
+	var err error
+	switch {
+	case len(re.Body) > 2 && re.Body[0] == 0x1f && re.Body[1] == 0x8b:
+		r, err = gzip.NewReader(bytes.NewReader(re.Body))
+	case len(re.Body) > 2 && re.Body[0]&0x0f == 8 && (uint16(re.Body[0])<<8|uint16(re.Body[1]))%31 == 0:
+		r, err = zlib.NewReader(bytes.NewReader(re.Body))
+	default:
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return
+	}
+	re.Body = body
+	re.Header.Del("Content-Encoding")
+	re.Header.Del("Content-Length")
+}
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jattschneider/rest/resttest"
+)
+
+func TestShouldGzipRequestForAllowedHost(t *testing.T) {
+	ts := gzipTestServer()
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	c := New(WithGzipRequestForHosts(u.Hostname()))
+
+	re, err := c.Post(ts.URL, strings.NewReader("{\"someProperty\":\"someValue\"}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.Header, "X-Request-Content-Encoding", "gzip")
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}")
+}
+
+func TestShouldNotGzipRequestForOtherHosts(t *testing.T) {
+	ts := gzipTestServer()
+	defer ts.Close()
+
+	c := New(WithGzipRequestForHosts("api.example.com"))
+
+	re, err := c.Post(ts.URL, strings.NewReader("{\"someProperty\":\"someValue\"}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.Header, "X-Request-Content-Encoding", "")
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}")
+}
+
+func TestShouldPostJSONGzip(t *testing.T) {
+	ts := gzipTestServer()
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	c := New(WithGzipRequestForHosts(u.Hostname()))
+
+	re, err := c.PostJSONGzip(ts.URL, &struct{ SomeProperty string }{SomeProperty: "someValue"}, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.SentHeaders, "Content-Encoding", "gzip")
+	assertHeader(t, re.SentHeaders, "Content-Type", "application/json")
+	assertHeader(t, re.Header, "X-Request-Content-Encoding", "gzip")
+	assertHeader(t, re.Header, "Content-Encoding", "")
+	assertBody(t, re.BodyString(), "{\"SomeProperty\":\"someValue\"}")
+}
+
+func ExampleWithGzipRequestForHosts() {
+	ts := resttest.NewEchoServer()
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	c := New(WithGzipRequestForHosts(u.Hostname()))
+
+	payload := EncodeJSON(&struct{ SomeProperty string }{SomeProperty: "struct property value"})
+	re, err := c.Post(ts.URL, payload, JSONRequestCallback)
+	if err != nil {
+		return
+	}
+
+	fmt.Print(re.BodyString())
+	// Output: {"SomeProperty":"struct property value"}
+}
+
+func gzipTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rBody, err := resttest.ReadRequestBody(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("X-Request-Content-Encoding", r.Header.Get("Content-Encoding"))
+		resttest.WriteResponseBody(w, r, rBody)
+	}))
+}
+
+func TestShouldNotRetryCorruptGzipBody(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip at all"))
+	}))
+	defer ts.Close()
+
+	_, err := New(WithRetry(3, time.Millisecond)).Get(ts.URL, func(r *http.Request) {
+		r.Header.Set("Accept-Encoding", "gzip")
+	})
+	if err == nil {
+		t.Error("Expected a decompression error")
+	}
+	if n := atomic.LoadInt32(&attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldSniffCompressedBody(t *testing.T) {
+	payload := "{\"someProperty\":\"someValue\"}"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var zw io.WriteCloser = gzip.NewWriter(w)
+		if r.URL.Query().Get("format") == "zlib" {
+			zw = zlib.NewWriter(w)
+		}
+		zw.Write([]byte(payload))
+		zw.Close()
+	}))
+	defer ts.Close()
+
+	c := New(WithSniffCompression())
+	for _, format := range []string{"gzip", "zlib"} {
+		re, err := c.Get(ts.URL+"?format="+format, JSONRequestCallback)
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		assertBody(t, re.BodyString(), payload)
+	}
+
+	re, _ := New().Get(ts.URL, JSONRequestCallback)
+	if re.BodyString() == payload {
+		t.Error("Body should only be sniffed when enabled")
+	}
+}
+
+func TestShouldKeepUncompressedBodyWhenSniffing(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	c := New(WithSniffCompression())
+	re, err := c.Post(ts.URL, strings.NewReader("x^plain text"), nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "x^plain text")
+}
@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrMissingContentType is returned for a 2xx response with a body but no Content-Type header.
+var ErrMissingContentType = errors.New("rest: response has no Content-Type")
+
+// WithRequireResponseContentType makes 2xx responses carrying a body without a Content-Type header fail
+// with ErrMissingContentType instead of silently decoding into nothing.
+func WithRequireResponseContentType() Option {
+	return func(c *Client) {
+		c.requireContentType = true
+	}
+}
+
+func requireContentType(re *ResponseEntity) error {
+	if re.StatusCode < http.StatusOK || re.StatusCode >= http.StatusMultipleChoices || len(re.Body) == 0 {
+		return nil
+	}
+	if len(re.Header.Get("Content-Type")) == 0 {
+		return ErrMissingContentType
+	}
+	return nil
+}
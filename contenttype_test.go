@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldRequireResponseContentType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Content-Type"] = nil
+		w.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	}))
+	defer ts.Close()
+
+	c := New(WithRequireResponseContentType())
+	if _, err := c.Get(ts.URL, JSONRequestCallback); !errors.Is(err, ErrMissingContentType) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrMissingContentType, err)
+	}
+
+	if _, err := New().Get(ts.URL, JSONRequestCallback); err != nil {
+		t.Errorf("Content-Type should only be required when enabled: %v", err)
+	}
+}
+
+func TestShouldAcceptResponseWithContentType(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	c := New(WithRequireResponseContentType())
+	payload := EncodeJSON(&struct{ SomeProperty string }{SomeProperty: "struct property value"})
+	if _, err := c.Post(ts.URL, payload, JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
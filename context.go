@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// ExchangeContext is Exchange bound to ctx, which replaces the Client's own context for this request,
+// so it can be canceled and carries its deadline and values through to the transport.
+func (c *Client) ExchangeContext(ctx context.Context, url, method string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.withContext(ctx).Exchange(url, method, body, requestCallback)
+}
+
+// GetContext gets the content from the given URL, bound to ctx
+func (c *Client) GetContext(ctx context.Context, url string, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.withContext(ctx).Get(url, requestCallback)
+}
+
+// HeadContext returns the headers from the given URL, bound to ctx
+func (c *Client) HeadContext(ctx context.Context, url string, requestCallback func(r *http.Request)) (http.Header, error) {
+	return c.withContext(ctx).Head(url, requestCallback)
+}
+
+// PostContext posts body content to the given URL, bound to ctx
+func (c *Client) PostContext(ctx context.Context, url string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.withContext(ctx).Post(url, body, requestCallback)
+}
+
+// PutContext puts the body content to the given URL, bound to ctx
+func (c *Client) PutContext(ctx context.Context, url string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.withContext(ctx).Put(url, body, requestCallback)
+}
+
+// PatchContext patches the body content to the given URL, bound to ctx
+func (c *Client) PatchContext(ctx context.Context, url string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.withContext(ctx).Patch(url, body, requestCallback)
+}
+
+// OptionsContext requests the communication options available for the given URL, bound to ctx
+func (c *Client) OptionsContext(ctx context.Context, url string, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.withContext(ctx).Options(url, requestCallback)
+}
+
+// DeleteContext deletes from the given URL, bound to ctx
+func (c *Client) DeleteContext(ctx context.Context, url string, requestCallback func(r *http.Request)) error {
+	return c.withContext(ctx).Delete(url, requestCallback)
+}
+
+func (c *Client) withContext(ctx context.Context) *Client {
+	cc := *c
+	cc.ctx = ctx
+	return &cc
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShouldGetContext(t *testing.T) {
+	type key struct{}
+	var value interface{}
+	c := New(WithDryRun(func(r *http.Request) {
+		value = r.Context().Value(key{})
+	}))
+
+	ctx := context.WithValue(context.Background(), key{}, "someValue")
+	if _, err := c.GetContext(ctx, "http://api.example.com/users", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if value != "someValue" {
+		t.Errorf("Expected context value: [%v] got: [%v]", "someValue", value)
+	}
+}
+
+func TestShouldCancelPostContext(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := New().PostContext(ctx, ts.URL, strings.NewReader("{}"), JSONRequestCallback)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error: [%v] got: [%v]", context.Canceled, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected request to be canceled early, took: [%v]", elapsed)
+	}
+}
@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CSVOption configures how DecodeCSV reads a body.
+type CSVOption func(r *csv.Reader)
+
+// CSVDelimiter sets the field delimiter, e.g. ';' or '\t'.
+func CSVDelimiter(delimiter rune) CSVOption {
+	return func(r *csv.Reader) {
+		r.Comma = delimiter
+	}
+}
+
+// DecodeCSV decodes the CSV body into the slice of structs pointed to by v. The header row is mapped to the
+// struct fields by their `csv` tag or name; a field whose column is missing from the header is an error.
+// A response without content sets v to an empty slice.
+func (re *ResponseEntity) DecodeCSV(v interface{}, opts ...CSVOption) error {
+	slice := reflect.ValueOf(v)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice || indirectType(slice.Elem().Type().Elem()).Kind() != reflect.Struct {
+		return errors.New("rest: DecodeCSV expects a pointer to a slice of structs")
+	}
+	if re.noContent() {
+		setZero(v)
+		return nil
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	structType := indirectType(elemType)
+
+	r := csv.NewReader(bytes.NewReader(re.Body))
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	fields := make(map[int]int)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		column, ok := columns[name]
+		if !ok {
+			return fmt.Errorf("rest: CSV header has no column %q for field %s", name, field.Name)
+		}
+		fields[i] = column
+	}
+
+	for line, record := range records[1:] {
+		elem := reflect.New(structType).Elem()
+		for field, column := range fields {
+			// ragged rows are possible when an option sets the reader's FieldsPerRecord to -1
+			if column >= len(record) {
+				return fmt.Errorf("rest: CSV line %d has no column %q", line+2, records[0][column])
+			}
+			if err := setCSVField(elem.Field(field), record[column]); err != nil {
+				return fmt.Errorf("rest: CSV line %d column %q: %v", line+2, records[0][column], err)
+			}
+		}
+		if elemType.Kind() == reflect.Ptr {
+			elem = elem.Addr()
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func setCSVField(fv reflect.Value, s string) error {
+	if fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+		return nil
+	}
+
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		fv.SetBool(b)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		fv.SetInt(i)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		fv.SetUint(u)
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		fv.SetFloat(f)
+		return err
+	}
+	return fmt.Errorf("unsupported field type %v", fv.Type())
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvReportRow struct {
+	ID      int       `csv:"id"`
+	Name    string    `csv:"name"`
+	Amount  float64   `csv:"amount"`
+	Active  bool      `csv:"active"`
+	Created time.Time `csv:"created"`
+	Ignored string    `csv:"-"`
+}
+
+func TestShouldDecodeCSV(t *testing.T) {
+	re := ResponseEntity{Body: []byte("id,name,amount,active,created,extra\n" +
+		"1,\"Schneider, José\",10.5,true,2020-01-02T03:04:05Z,x\n" +
+		"2,\"say \"\"hi\"\"\",,false,2020-02-03T04:05:06Z,y\n")}
+
+	var rows []csvReportRow
+	if err := re.DecodeCSV(&rows); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	expected := []csvReportRow{
+		{ID: 1, Name: "Schneider, José", Amount: 10.5, Active: true, Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{ID: 2, Name: "say \"hi\"", Created: time.Date(2020, 2, 3, 4, 5, 6, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected rows: [%v] got: [%v]", expected, rows)
+	}
+}
+
+func TestShouldDecodeCSVWithDelimiter(t *testing.T) {
+	re := ResponseEntity{Body: []byte("id;name\n1;first\n2;second\n")}
+
+	var rows []*struct {
+		ID   int    `csv:"id"`
+		Name string `csv:"name"`
+	}
+	if err := re.DecodeCSV(&rows, CSVDelimiter(';')); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(rows) != 2 || rows[1].ID != 2 || rows[1].Name != "second" {
+		t.Errorf("Expected 2 rows got: [%v]", rows)
+	}
+}
+
+func TestShouldFailDecodeCSVOnHeaderMismatch(t *testing.T) {
+	re := ResponseEntity{Body: []byte("id,title\n1,first\n")}
+
+	var rows []csvReportRow
+	if err := re.DecodeCSV(&rows); err == nil {
+		t.Error("Expected header mismatch error")
+	}
+
+	if err := re.DecodeCSV(rows); err == nil {
+		t.Error("Expected error for non pointer rows")
+	}
+}
+
+func TestShouldFailDecodeCSVOnShortRow(t *testing.T) {
+	re := ResponseEntity{Body: []byte("id,name\n1,first\n2\n")}
+
+	var rows []struct {
+		ID   int    `csv:"id"`
+		Name string `csv:"name"`
+	}
+	err := re.DecodeCSV(&rows, func(r *csv.Reader) {
+		r.FieldsPerRecord = -1
+	})
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected short row error got: [%v]", err)
+	}
+}
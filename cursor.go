@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// PaginateCursor gets the given URL and the pages after it, decoding each JSON page into a T, for APIs
+// paginating with query parameters rather than Link headers. next returns the query parameters asking
+// for the page after page, e.g. its cursor or the next offset, which replace those of the previous
+// request, or false after the last page. As with Paginate an error, including a non 2xx response and
+// ErrPaginationLimit past the pagination deadline or maximum page count, ends the pages.
+func PaginateCursor[T any](c *Client, rawURL string, next func(page T) (url.Values, bool), requestCallback func(r *http.Request)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		maxPages := c.maxPages
+		if maxPages <= 0 {
+			maxPages = DefaultMaxPages
+		}
+
+		cc, outOfTime, cancel := c.paginationContext()
+		defer cancel()
+
+		for pages := 0; ; pages++ {
+			if pages >= maxPages {
+				yield(zero, fmt.Errorf("%w: %d pages", ErrPaginationLimit, maxPages))
+				return
+			}
+			if outOfTime() {
+				yield(zero, c.paginationDeadlineError())
+				return
+			}
+
+			re, err := cc.Get(rawURL, requestCallback)
+			if err != nil && outOfTime() {
+				yield(zero, c.paginationDeadlineError())
+				return
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if re.StatusCode < http.StatusOK || re.StatusCode >= http.StatusMultipleChoices {
+				yield(zero, fmt.Errorf("rest: page %s failed with status %d", rawURL, re.StatusCode))
+				return
+			}
+
+			var page T
+			if err := re.DecodeWith(DecodeJSON, &page); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+
+			query, ok := next(page)
+			if !ok {
+				return
+			}
+			if rawURL, err = replaceQuery(rawURL, query); err != nil {
+				yield(zero, err)
+				return
+			}
+		}
+	}
+}
+
+// replaceQuery sets the query parameters of rawURL in values, keeping the others.
+func replaceQuery(rawURL string, values url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for key, value := range values {
+		query[key] = value
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type cursorPage struct {
+	Items      []int  `json:"items"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// cursorTestServer serves items 1 to total, two per page, with an opaque cursor in the body. Pages after
+// failAt answer 429 Too Many Requests when failAt is positive, and every page takes delay.
+func cursorTestServer(total, failAt int, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		if failAt > 0 && offset >= failAt {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":"slow down"}`))
+			return
+		}
+		page := cursorPage{}
+		for i := offset + 1; i <= total && i <= offset+2; i++ {
+			page.Items = append(page.Items, i)
+		}
+		if offset+2 < total {
+			page.NextCursor = strconv.Itoa(offset + 2)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestShouldPaginateWithCursor(t *testing.T) {
+	ts := cursorTestServer(5, 0, 0)
+	defer ts.Close()
+
+	next := func(page cursorPage) (url.Values, bool) {
+		return url.Values{"cursor": {page.NextCursor}}, len(page.NextCursor) > 0
+	}
+	var items []int
+	for page, err := range PaginateCursor(New(), ts.URL+"/items?filter=open", next, JSONRequestCallback) {
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		items = append(items, page.Items...)
+	}
+	if !reflect.DeepEqual(items, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected items: [%v] got: [%v]", []int{1, 2, 3, 4, 5}, items)
+	}
+}
+
+func TestShouldStopCursorPaginationAtMaxPages(t *testing.T) {
+	ts := cursorTestServer(100, 0, 0)
+	defer ts.Close()
+
+	offset := 0
+	next := func(page cursorPage) (url.Values, bool) {
+		offset += len(page.Items)
+		return url.Values{"cursor": {strconv.Itoa(offset)}}, len(page.Items) > 0
+	}
+	var pages int
+	var err error
+	for _, err = range PaginateCursor(New(WithMaxPages(3)), ts.URL, next, nil) {
+		if err == nil {
+			pages++
+		}
+	}
+	if !errors.Is(err, ErrPaginationLimit) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrPaginationLimit, err)
+	}
+	if pages != 3 {
+		t.Errorf("Expected pages: [%v] got: [%v]", 3, pages)
+	}
+}
+
+func TestShouldFailCursorPaginationOnErrorStatus(t *testing.T) {
+	ts := cursorTestServer(10, 4, 0)
+	defer ts.Close()
+
+	next := func(page cursorPage) (url.Values, bool) {
+		return url.Values{"cursor": {page.NextCursor}}, len(page.NextCursor) > 0
+	}
+	var items []int
+	var err error
+	for page, pageErr := range PaginateCursor(New(), ts.URL, next, nil) {
+		if err = pageErr; err == nil {
+			items = append(items, page.Items...)
+		}
+	}
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("Expected status error got: [%v]", err)
+	}
+	if !reflect.DeepEqual(items, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected items: [%v] got: [%v]", []int{1, 2, 3, 4}, items)
+	}
+}
+
+func TestShouldStopCursorPaginationAtDeadline(t *testing.T) {
+	ts := cursorTestServer(1000, 0, 50*time.Millisecond)
+	defer ts.Close()
+
+	next := func(page cursorPage) (url.Values, bool) {
+		return url.Values{"cursor": {page.NextCursor}}, len(page.NextCursor) > 0
+	}
+	var err error
+	for _, err = range PaginateCursor(New(WithPaginationDeadline(120*time.Millisecond)), ts.URL, next, nil) {
+	}
+	if !errors.Is(err, ErrPaginationLimit) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrPaginationLimit, err)
+	}
+}
+
+func TestShouldReplaceQuery(t *testing.T) {
+	rawURL, err := replaceQuery("/items?filter=open&offset=0", url.Values{"offset": {"20"}})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, rawURL, "/items?filter=open&offset=20")
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// WithDefaultBody sends body with POST, PUT and PATCH requests made with a nil body, for proxies rejecting
+// them without one (411 Length Required). A nil body sends an empty JSON object.
+func WithDefaultBody(body []byte) Option {
+	return func(c *Client) {
+		if body == nil {
+			body = []byte("{}")
+		}
+		c.defaultBody = body
+	}
+}
+
+func (c *Client) defaultRequestBody(method string) io.Reader {
+	if c.defaultBody == nil {
+		return nil
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return bytes.NewReader(c.defaultBody)
+	}
+	return nil
+}
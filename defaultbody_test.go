@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestShouldSendDefaultBody(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	c := New(WithDefaultBody(nil))
+	re, err := c.Patch(ts.URL, nil, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "{}")
+
+	c = New(WithDefaultBody([]byte("[]")))
+	re, err = c.Post(ts.URL, nil, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "[]")
+
+	var sent int64 = -1
+	c = New(WithDefaultBody(nil), WithDryRun(func(r *http.Request) {
+		sent = r.ContentLength
+	}))
+	c.Get(ts.URL, JSONRequestCallback)
+	if sent != 0 {
+		t.Errorf("Expected GET without body got content length: [%v]", sent)
+	}
+}
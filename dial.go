@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithDialRetries retries establishing a connection up to n more times, waiting backoff in between,
+// so a transient DNS or connect failure doesn't fail the request.
+func WithDialRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.dialRetries = n
+		c.dialBackoff = backoff
+	}
+}
+
+func retryDial(dial dialFunc, retries int, backoff time.Duration) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		for attempt := 0; ; attempt++ {
+			conn, err := dial(ctx, network, addr)
+			if err == nil || attempt >= retries || ctx.Err() != nil {
+				return conn, err
+			}
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShouldRetryDial(t *testing.T) {
+	dials := 0
+	dial := retryDial(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		if dials < 3 {
+			return nil, errors.New("connection refused")
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}, 3, time.Millisecond)
+
+	conn, err := dial(context.Background(), "tcp", "localhost:80")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	conn.Close()
+
+	if dials != 3 {
+		t.Errorf("Expected dials: [%v] got: [%v]", 3, dials)
+	}
+}
+
+func TestShouldDialFlakyListenerOnRetry(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	server := &http.Server{Handler: http.HandlerFunc(testHandler)}
+	defer server.Close()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+		server.Serve(l)
+	}()
+
+	c := New(WithDialRetries(10, 50*time.Millisecond))
+	re, err := c.Get("http://"+addr, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+}
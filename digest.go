@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"strings"
+)
+
+// ErrDigestMismatch is returned when a response body doesn't match its Content-MD5 or Digest header.
+var ErrDigestMismatch = errors.New("rest: response body digest mismatch")
+
+// WithVerifyDigest verifies response bodies against their Content-MD5 or Digest header. Requests without an
+// Accept-Encoding header ask for gzip explicitly, so the digest is checked on the body as sent.
+func WithVerifyDigest() Option {
+	return func(c *Client) {
+		c.verifyDigest = true
+	}
+}
+
+var digestAlgorithms = map[string]func() hash.Hash{
+	"md5":     md5.New,
+	"sha":     sha1.New,
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+func verifyDigest(re *ResponseEntity) error {
+	if contentMD5 := re.Header.Get("Content-MD5"); len(contentMD5) > 0 {
+		if !digestMatches(md5.New, contentMD5, re.Body) {
+			return ErrDigestMismatch
+		}
+	}
+
+	for _, digest := range re.Header.Values("Digest") {
+		for _, instance := range strings.Split(digest, ",") {
+			parts := strings.SplitN(strings.TrimSpace(instance), "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			newHash, ok := digestAlgorithms[strings.ToLower(parts[0])]
+			if !ok {
+				continue
+			}
+			if !digestMatches(newHash, parts[1], re.Body) {
+				return ErrDigestMismatch
+			}
+		}
+	}
+	return nil
+}
+
+func digestMatches(newHash func() hash.Hash, expected string, body []byte) bool {
+	h := newHash()
+	h.Write(body)
+	actual := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(strings.TrimSpace(expected))) == 1
+}
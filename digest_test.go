@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldVerifyMatchingDigest(t *testing.T) {
+	body := []byte("{\"someProperty\":\"someValue\"}")
+	sum := sha256.Sum256(body)
+	md5Sum := md5.Sum(body)
+	ts := digestTestServer(body, http.Header{
+		"Digest":      []string{"SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])},
+		"Content-Md5": []string{base64.StdEncoding.EncodeToString(md5Sum[:])},
+	})
+	defer ts.Close()
+
+	c := New(WithVerifyDigest())
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), string(body))
+}
+
+func TestShouldVerifyDigestOfGzipBody(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	zw.Close()
+	sum := sha256.Sum256(compressed.Bytes())
+	ts := digestTestServer(compressed.Bytes(), http.Header{
+		"Content-Encoding": []string{"gzip"},
+		"Digest":           []string{"SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])},
+	})
+	defer ts.Close()
+
+	re, err := New(WithVerifyDigest()).Get(ts.URL, func(r *http.Request) {
+		r.Header.Set("Accept-Encoding", "gzip")
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}")
+}
+
+func TestShouldVerifyDigestOfGzipBodyWithoutAcceptEncoding(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	zw.Close()
+	sum := sha256.Sum256(compressed.Bytes())
+	ts := digestTestServer(compressed.Bytes(), http.Header{
+		"Content-Encoding": []string{"gzip"},
+		"Digest":           []string{"SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])},
+	})
+	defer ts.Close()
+
+	re, err := New(WithVerifyDigest()).Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}")
+	assertHeader(t, re.SentHeaders, "Accept-Encoding", "gzip")
+}
+
+func TestShouldRejectMismatchingDigest(t *testing.T) {
+	sum := sha256.Sum256([]byte("something else"))
+	ts := digestTestServer([]byte("{\"someProperty\":\"someValue\"}"), http.Header{
+		"Digest": []string{"SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])},
+	})
+	defer ts.Close()
+
+	c := New(WithVerifyDigest())
+	if _, err := c.Get(ts.URL, JSONRequestCallback); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrDigestMismatch, err)
+	}
+
+	if _, err := New().Get(ts.URL, JSONRequestCallback); err != nil {
+		t.Errorf("Digest should only be verified when enabled: %v", err)
+	}
+}
+
+func digestTestServer(body []byte, header http.Header) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.Write(body)
+	}))
+}
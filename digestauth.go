@@ -0,0 +1,202 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WithDigestAuth authenticates with RFC 7616 Digest authentication: a request answered with a 401 Digest
+// challenge is sent once more with the computed Authorization. Later requests reuse the challenge, counting
+// its nonce, until the server asks for a new one. MD5 and SHA-256 (and their -sess variants) are supported,
+// with the auth and auth-int qop.
+func WithDigestAuth(user, password string) Option {
+	d := &digestAuth{user: user, password: password}
+	return WithMiddleware(d.middleware)
+}
+
+type digestAuth struct {
+	user     string
+	password string
+
+	mu        sync.Mutex
+	challenge map[string]string
+	nc        int
+}
+
+type digestChallenge struct {
+	params map[string]string
+	nc     int
+}
+
+func (d *digestAuth) middleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if challenge, ok := d.nextChallenge(nil); ok {
+			if err := d.authorize(req, challenge, body); err != nil {
+				return nil, err
+			}
+		}
+		res, err := next(req)
+		if err != nil || res.StatusCode != http.StatusUnauthorized {
+			return res, err
+		}
+
+		params, ok := parseDigestChallenge(res.Header.Values("Www-Authenticate"))
+		if !ok {
+			return res, nil
+		}
+		challenge, _ := d.nextChallenge(params)
+		retry := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			retry.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		if err := d.authorize(retry, challenge, body); err != nil {
+			return res, nil
+		}
+		res.Body.Close()
+		return next(retry)
+	}
+}
+
+// nextChallenge stores params as the current challenge, when given, and returns the current challenge
+// with its next nonce count.
+func (d *digestAuth) nextChallenge(params map[string]string) (digestChallenge, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if params != nil {
+		d.challenge, d.nc = params, 0
+	}
+	if d.challenge == nil {
+		return digestChallenge{}, false
+	}
+	d.nc++
+	return digestChallenge{params: d.challenge, nc: d.nc}, true
+}
+
+func (d *digestAuth) authorize(req *http.Request, challenge digestChallenge, body []byte) error {
+	cnonce := make([]byte, 16)
+	if _, err := rand.Read(cnonce); err != nil {
+		return err
+	}
+	authorization, err := digestAuthorization(req.Method, req.URL.RequestURI(), body, d.user, d.password, challenge, hex.EncodeToString(cnonce))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	return nil
+}
+
+func digestAuthorization(method, uri string, body []byte, user, password string, challenge digestChallenge, cnonce string) (string, error) {
+	params := challenge.params
+	algorithm := params["algorithm"]
+	if len(algorithm) == 0 {
+		algorithm = "MD5"
+	}
+	var newHash func() hash.Hash
+	switch strings.ToUpper(strings.TrimSuffix(strings.ToLower(algorithm), "-sess")) {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("rest: unsupported digest algorithm %q", algorithm)
+	}
+	h := func(s string) string {
+		sum := newHash()
+		sum.Write([]byte(s))
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	qop := ""
+	for _, option := range strings.Split(params["qop"], ",") {
+		switch option = strings.TrimSpace(option); {
+		case option == "auth":
+			qop = option
+		case option == "auth-int" && len(qop) == 0:
+			qop = option
+		}
+	}
+	if len(params["qop"]) > 0 && len(qop) == 0 {
+		return "", fmt.Errorf("rest: unsupported digest qop %q", params["qop"])
+	}
+
+	nonce, nc := params["nonce"], fmt.Sprintf("%08x", challenge.nc)
+	ha1 := h(user + ":" + params["realm"] + ":" + password)
+	if strings.HasSuffix(strings.ToLower(algorithm), "-sess") {
+		ha1 = h(ha1 + ":" + nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+	if qop == "auth-int" {
+		ha2 = h(method + ":" + uri + ":" + h(string(body)))
+	}
+
+	response := h(ha1 + ":" + nonce + ":" + ha2)
+	if len(qop) > 0 {
+		response = h(ha1 + ":" + nonce + ":" + nc + ":" + cnonce + ":" + qop + ":" + ha2)
+	}
+
+	authorization := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=%s, response="%s"`,
+		user, params["realm"], nonce, uri, algorithm, response)
+	if len(qop) > 0 {
+		authorization += fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s"`, qop, nc, cnonce)
+	}
+	if opaque, ok := params["opaque"]; ok {
+		authorization += fmt.Sprintf(`, opaque="%s"`, opaque)
+	}
+	return authorization, nil
+}
+
+// parseDigestChallenge returns the parameters of the first Digest challenge of the WWW-Authenticate headers.
+func parseDigestChallenge(values []string) (map[string]string, bool) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < 7 || !strings.EqualFold(value[:7], "Digest ") {
+			continue
+		}
+		return parseAuthParams(value[7:]), true
+	}
+	return nil, false
+}
+
+// parseAuthParams parses comma separated name=value parameters, whose values may be quoted.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		name := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var value string
+		if strings.HasPrefix(s, "\"") {
+			var ok bool
+			if value, s, ok = parseQuoted(s); !ok {
+				break
+			}
+		} else if comma := strings.IndexByte(s, ','); comma >= 0 {
+			value, s = strings.TrimSpace(s[:comma]), s[comma:]
+		} else {
+			value, s = strings.TrimSpace(s), ""
+		}
+		params[name] = value
+	}
+	return params
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jattschneider/rest/resttest"
+)
+
+func TestShouldComputeDigestAuthorization(t *testing.T) {
+	// RFC 7616 section 3.9.1
+	params := map[string]string{
+		"realm":  "http-auth@example.org",
+		"qop":    "auth, auth-int",
+		"nonce":  "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+		"opaque": "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+	}
+	cases := map[string]string{
+		"MD5":     "8ca523f5e9506fed4657c9700eebdbec",
+		"SHA-256": "753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1",
+	}
+	for algorithm, response := range cases {
+		params["algorithm"] = algorithm
+		authorization, err := digestAuthorization(http.MethodGet, "/dir/index.html", nil, "Mufasa", "Circle of Life",
+			digestChallenge{params: params, nc: 1}, "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ")
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+
+		if got := parseAuthParams(strings.TrimPrefix(authorization, "Digest "))["response"]; got != response {
+			t.Errorf("Expected %v response: [%v] got: [%v]", algorithm, response, got)
+		}
+	}
+}
+
+func TestShouldParseDigestChallenge(t *testing.T) {
+	params, ok := parseDigestChallenge([]string{"Basic realm=\"x\"", "Digest realm=\"api, v2\", qop=\"auth\", nonce=\"abc\", algorithm=MD5, stale=FALSE"})
+	if !ok {
+		t.Fatal("Expected a Digest challenge")
+	}
+
+	if params["realm"] != "api, v2" || params["qop"] != "auth" || params["nonce"] != "abc" || params["algorithm"] != "MD5" || params["stale"] != "FALSE" {
+		t.Errorf("Expected challenge params got: [%v]", params)
+	}
+}
+
+func TestShouldAuthenticateWithDigest(t *testing.T) {
+	ts, challenges := digestAuthTestServer()
+	defer ts.Close()
+
+	c := New(WithDigestAuth("jose", "secret"))
+	for i := 0; i < 2; i++ {
+		re, err := c.Post(ts.URL+"/orders?page=1", strings.NewReader("{}"), JSONRequestCallback)
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		assertStatusCode(t, re.StatusCode, http.StatusOK)
+		assertBody(t, re.BodyString(), "{}")
+	}
+
+	if n := atomic.LoadInt32(challenges); n != 1 {
+		t.Errorf("Expected challenges: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldNotRetryDigestWithWrongPassword(t *testing.T) {
+	ts, challenges := digestAuthTestServer()
+	defer ts.Close()
+
+	re, err := New(WithDigestAuth("jose", "wrong")).Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusUnauthorized)
+	if n := atomic.LoadInt32(challenges); n != 2 {
+		t.Errorf("Expected challenges: [%v] got: [%v]", 2, n)
+	}
+}
+
+// digestAuthTestServer accepts jose:secret with MD5 Digest auth and qop=auth, echoing the request body.
+func digestAuthTestServer() (*httptest.Server, *int32) {
+	challenges := new(int32)
+	md5Hex := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := parseAuthParams(strings.TrimPrefix(r.Header.Get("Authorization"), "Digest "))
+		ha1 := md5Hex("jose:test:secret")
+		ha2 := md5Hex(r.Method + ":" + params["uri"])
+		expected := md5Hex(ha1 + ":n0nce:" + params["nc"] + ":" + params["cnonce"] + ":auth:" + ha2)
+		if params["response"] != expected || params["uri"] != r.URL.RequestURI() || params["opaque"] != "0paque" {
+			atomic.AddInt32(challenges, 1)
+			w.Header().Set("WWW-Authenticate", "Digest realm=\"test\", qop=\"auth\", nonce=\"n0nce\", opaque=\"0paque\"")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rBody, _ := resttest.ReadRequestBody(r)
+		w.Write(rBody)
+	}))
+	return ts, challenges
+}
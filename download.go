@@ -0,0 +1,273 @@
+package rest
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch is returned by Download when the downloaded file doesn't match its expected checksum.
+var ErrChecksumMismatch = errors.New("rest: download checksum mismatch")
+
+// DownloadOption configures a Download.
+type DownloadOption func(d *download)
+
+type download struct {
+	progress func(read, total int64)
+	checksum hash.Hash
+	sum      string
+	resume   bool
+}
+
+// DownloadProgress calls fn as the download is written, with the bytes read so far and the total, -1 when
+// the server didn't send a Content-Length.
+func DownloadProgress(fn func(read, total int64)) DownloadOption {
+	return func(d *download) {
+		d.progress = fn
+	}
+}
+
+// DownloadChecksum verifies the downloaded file hashes with h to the hex encoded sum, e.g.
+// DownloadChecksum(sha256.New(), "9f86d0..."). A mismatching file is removed.
+func DownloadChecksum(h hash.Hash, sum string) DownloadOption {
+	return func(d *download) {
+		d.checksum = h
+		d.sum = sum
+	}
+}
+
+// DownloadResume resumes an interrupted download into an existing destination file with a Range request,
+// when the server advertised Accept-Ranges and a strong ETag for it. The ETag is kept next to the file,
+// with an ".etag" suffix, until the download completes; if the content changed since, the server's If-Range
+// check sends it whole again. Resuming needs destPath to be a file path.
+func DownloadResume() DownloadOption {
+	return func(d *download) {
+		d.resume = true
+	}
+}
+
+// Download streams the content of the given URL to destPath, or to the file named by its Content-Disposition
+// (or else its URL path) when destPath is a directory. Non 2xx responses and bodies shorter than their
+// Content-Length are errors; the returned ResponseEntity has the response status and headers but no body.
+// Unless resuming, the file is only put in place once complete, so a failed download leaves destPath as is.
+func (c *Client) Download(ctx context.Context, url, destPath string, opts ...DownloadOption) (ResponseEntity, error) {
+	d := &download{}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	var offset int64
+	var etag string
+	if d.resume {
+		offset, etag = partialDownload(destPath)
+	}
+	se, err := c.withContext(ctx).GetStream(url, func(r *http.Request) {
+		if !d.resume {
+			return
+		}
+		// Ranges are of the identity encoding, so the transport mustn't ask for gzip.
+		r.Header.Set("Accept-Encoding", "identity")
+		if offset > 0 {
+			r.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			r.Header.Set("If-Range", etag)
+		}
+	})
+	if err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+	defer se.Body.Close()
+
+	re := ResponseEntity{StatusCode: se.StatusCode, Header: se.Header}
+	if se.StatusCode < http.StatusOK || se.StatusCode >= http.StatusMultipleChoices {
+		return re, fmt.Errorf("rest: download of %s failed with status %d", url, se.StatusCode)
+	}
+
+	if info, err := os.Stat(destPath); err == nil && info.IsDir() {
+		destPath = filepath.Join(destPath, downloadFilename(&re, url))
+	}
+
+	total := contentLength(se.Header)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if start, size, ok := contentRange(se.Header); se.StatusCode == http.StatusPartialContent && offset > 0 && start == offset && ok {
+		flags = os.O_WRONLY | os.O_APPEND
+		total = size
+		if err := d.hashPartial(destPath); err != nil {
+			return re, err
+		}
+	} else if se.StatusCode == http.StatusPartialContent {
+		return re, fmt.Errorf("rest: unexpected Content-Range %q resuming at %d", se.Header.Get("Content-Range"), offset)
+	} else {
+		offset = 0
+	}
+	if d.resume {
+		rememberResumable(destPath, se.Header)
+	}
+
+	// resumable downloads are written in place so an interruption leaves the part to resume, others go to a
+	// temporary file renamed over destPath once complete, so a failure never leaves a truncated file behind
+	writePath := destPath
+	var f *os.File
+	if d.resume {
+		f, err = os.OpenFile(destPath, flags, 0666)
+	} else {
+		f, writePath, err = createTempDownload(destPath)
+	}
+	if err != nil {
+		return re, err
+	}
+	_, err = d.copy(f, se.Body, total, offset)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && d.checksum != nil && hex.EncodeToString(d.checksum.Sum(nil)) != d.sum {
+		os.Remove(writePath)
+		if d.resume {
+			os.Remove(destPath + ".etag")
+		}
+		return re, ErrChecksumMismatch
+	}
+	if err == nil && writePath != destPath {
+		err = os.Rename(writePath, destPath)
+	}
+	if err != nil {
+		if writePath != destPath {
+			os.Remove(writePath)
+		}
+		return re, err
+	}
+	if d.resume {
+		os.Remove(destPath + ".etag")
+	}
+	return re, nil
+}
+
+// createTempDownload creates a new file next to destPath to download into, honouring the umask as
+// os.Create does.
+func createTempDownload(destPath string) (*os.File, string, error) {
+	dir, name := filepath.Split(destPath)
+	for i := 0; ; i++ {
+		tempPath := filepath.Join(dir, "."+name+"."+strconv.FormatInt(time.Now().UnixNano()+int64(i), 36)+".tmp")
+		f, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if os.IsExist(err) && i < 10000 {
+			continue
+		}
+		return f, tempPath, err
+	}
+}
+
+// copy writes body to w, starting offset bytes into a download of total bytes, reporting progress and
+// feeding the checksum.
+func (d *download) copy(w io.Writer, body io.Reader, total, offset int64) (int64, error) {
+	if d.checksum != nil {
+		w = io.MultiWriter(w, d.checksum)
+	}
+	if d.progress != nil {
+		body = &progressReader{r: body, read: offset, total: total, fn: d.progress}
+	}
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, err
+	}
+	if total >= 0 && offset+n != total {
+		return n, fmt.Errorf("rest: downloaded %d of %d bytes: %w", offset+n, total, io.ErrUnexpectedEOF)
+	}
+	return n, nil
+}
+
+// partialDownload returns the size of the partially downloaded destPath and the ETag it was downloaded with,
+// zero when there's nothing to resume.
+func partialDownload(destPath string) (int64, string) {
+	info, err := os.Stat(destPath)
+	if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+		return 0, ""
+	}
+	etag, err := ioutil.ReadFile(destPath + ".etag")
+	if err != nil || len(etag) == 0 {
+		return 0, ""
+	}
+	return info.Size(), string(etag)
+}
+
+// rememberResumable keeps the ETag of a download the server accepts Range requests for next to destPath.
+func rememberResumable(destPath string, header http.Header) {
+	etag := header.Get("ETag")
+	if header.Get("Accept-Ranges") != "bytes" || len(etag) == 0 || strings.HasPrefix(etag, "W/") {
+		os.Remove(destPath + ".etag")
+		return
+	}
+	ioutil.WriteFile(destPath+".etag", []byte(etag), 0666)
+}
+
+// hashPartial feeds the checksum with the part of the download already in destPath.
+func (d *download) hashPartial(destPath string) error {
+	if d.checksum == nil {
+		return nil
+	}
+	f, err := os.Open(destPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(d.checksum, f)
+	return err
+}
+
+// contentRange parses a "bytes start-end/size" Content-Range header.
+func contentRange(header http.Header) (int64, int64, bool) {
+	var start, end, size int64
+	if _, err := fmt.Sscanf(header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &size); err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
+
+func downloadFilename(re *ResponseEntity, rawURL string) string {
+	if name, ok := re.Filename(); ok {
+		return name
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		if name := path.Base(u.Path); name != "." && name != "/" {
+			return name
+		}
+	}
+	return "download"
+}
+
+// contentLength returns the Content-Length header, -1 when it's missing or invalid.
+func contentLength(header http.Header) int64 {
+	n, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// progressReader calls fn with the bytes read so far, out of total, after every read.
+type progressReader struct {
+	r     io.Reader
+	read  int64
+	total int64
+	fn    func(read, total int64)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.read += int64(n)
+		pr.fn(pr.read, pr.total)
+	}
+	return n, err
+}
@@ -0,0 +1,173 @@
+package rest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShouldDownloadToFile(t *testing.T) {
+	content := strings.Repeat("0123456789", 10000)
+	ts := downloadTestServer(content)
+	defer ts.Close()
+
+	sum := sha256.Sum256([]byte(content))
+	var read, total int64
+	dest := filepath.Join(t.TempDir(), "artifact.bin")
+	re, err := New().Download(context.Background(), ts.URL+"/artifact", dest,
+		DownloadProgress(func(r, t int64) { read, total = r, t }),
+		DownloadChecksum(sha256.New(), hex.EncodeToString(sum[:])))
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	if b, _ := ioutil.ReadFile(dest); string(b) != content {
+		t.Errorf("Expected file of: [%v] bytes got: [%v]", len(content), len(b))
+	}
+	if read != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("Expected progress: [%v/%v] got: [%v/%v]", len(content), len(content), read, total)
+	}
+}
+
+func TestShouldDownloadToDirectory(t *testing.T) {
+	ts := downloadTestServer("content")
+	defer ts.Close()
+
+	dir := t.TempDir()
+	if _, err := New().Download(context.Background(), ts.URL+"/files/42", dir); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "report.csv")); err != nil || string(b) != "content" {
+		t.Errorf("Expected file named by Content-Disposition got: [%v] [%v]", string(b), err)
+	}
+}
+
+func TestShouldRemoveDownloadWithChecksumMismatch(t *testing.T) {
+	ts := downloadTestServer("content")
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "artifact.bin")
+	_, err := New().Download(context.Background(), ts.URL, dest, DownloadChecksum(sha256.New(), "00"))
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrChecksumMismatch, err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("Expected file to be removed got: [%v]", err)
+	}
+}
+
+func TestShouldFailTruncatedDownload(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("short"))
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "artifact.bin")
+	ioutil.WriteFile(dest, []byte("previous"), 0666)
+	_, err := New().Download(context.Background(), ts.URL, dest)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected error: [%v] got: [%v]", io.ErrUnexpectedEOF, err)
+	}
+
+	if b, _ := ioutil.ReadFile(dest); string(b) != "previous" {
+		t.Errorf("Expected the previous file to be kept got: [%v]", string(b))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected no temporary file left got: [%v]", entries)
+	}
+}
+
+func TestShouldFailDownloadOnErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	re, err := New().Download(context.Background(), ts.URL, filepath.Join(t.TempDir(), "artifact.bin"))
+	if err == nil {
+		t.Error("Expected error for a 404")
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusNotFound)
+}
+
+// downloadTestServer serves content as report.csv, with its Content-Length.
+func downloadTestServer(content string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Disposition", "attachment; filename=\"report.csv\"")
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		io.WriteString(w, content)
+	}))
+}
+
+func TestShouldResumeDownload(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+	ts, ranges := rangeTestServer(content, "\"v1\"")
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "artifact.bin")
+	ioutil.WriteFile(dest, []byte(content[:4000]), 0600)
+	ioutil.WriteFile(dest+".etag", []byte("\"v1\""), 0600)
+
+	sum := sha256.Sum256([]byte(content))
+	var read int64
+	_, err := New().Download(context.Background(), ts.URL, dest, DownloadResume(),
+		DownloadChecksum(sha256.New(), hex.EncodeToString(sum[:])),
+		DownloadProgress(func(r, t int64) { read = r }))
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if b, _ := ioutil.ReadFile(dest); string(b) != content {
+		t.Errorf("Expected resumed file of: [%v] bytes got: [%v]", len(content), len(b))
+	}
+	if strings.Join(*ranges, ",") != "bytes=4000-" || read != int64(len(content)) {
+		t.Errorf("Expected a single range request got: [%v] progress: [%v]", *ranges, read)
+	}
+	if _, err := os.Stat(dest + ".etag"); !os.IsNotExist(err) {
+		t.Errorf("Expected ETag file to be removed got: [%v]", err)
+	}
+}
+
+func TestShouldRestartDownloadOfChangedContent(t *testing.T) {
+	content := strings.Repeat("abcdefghij", 1000)
+	ts, _ := rangeTestServer(content, "\"v2\"")
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "artifact.bin")
+	ioutil.WriteFile(dest, []byte(strings.Repeat("0", 4000)), 0600)
+	ioutil.WriteFile(dest+".etag", []byte("\"v1\""), 0600)
+
+	if _, err := New().Download(context.Background(), ts.URL, dest, DownloadResume()); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != content {
+		t.Errorf("Expected the whole new content got: [%v] bytes", len(b))
+	}
+}
+
+// rangeTestServer serves content with the given ETag and Range support, recording the Range headers.
+func rangeTestServer(content, etag string) (*httptest.Server, *[]string) {
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rangeHeader := r.Header.Get("Range"); len(rangeHeader) > 0 {
+			ranges = append(ranges, rangeHeader)
+		}
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+	}))
+	return ts, &ranges
+}
@@ -0,0 +1,12 @@
+package rest
+
+import "net/http"
+
+// WithDryRun builds every request as usual, default headers and callbacks included, but hands the final
+// request to fn instead of sending it and returns an empty 200 response. It's meant for unit testing
+// request construction without a server.
+func WithDryRun(fn func(*http.Request)) Option {
+	return func(c *Client) {
+		c.dryRun = fn
+	}
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestShouldDryRunRequests(t *testing.T) {
+	var built *http.Request
+	var body string
+	c := New(WithAcceptCharset("utf-8"), WithDryRun(func(r *http.Request) {
+		built = r
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+
+	// nothing listens on port 1, a real request would fail
+	re, err := c.Put("http://127.0.0.1:1/users/42", strings.NewReader("{\"name\":\"jose\"}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	if built == nil {
+		t.Fatal("Expected the dry run callback to see the request")
+	}
+
+	if built.Method != http.MethodPut || built.URL.String() != "http://127.0.0.1:1/users/42" {
+		t.Errorf("Unexpected request: [%v %v]", built.Method, built.URL)
+	}
+	assertHeader(t, built.Header, "Accept-Charset", "utf-8")
+	assertHeader(t, built.Header, "Content-Type", "application/json")
+	assertBody(t, body, "{\"name\":\"jose\"}")
+}
+
+func TestShouldDryRunStreams(t *testing.T) {
+	var built *http.Request
+	c := New(WithAcceptCharset("utf-8"), WithDryRun(func(r *http.Request) {
+		built = r
+	}))
+
+	// nothing listens on port 1, a real request would fail
+	se, err := c.GetStream("http://127.0.0.1:1/events", nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer se.Body.Close()
+
+	assertStatusCode(t, se.StatusCode, http.StatusOK)
+	if built == nil {
+		t.Fatal("Expected the dry run callback to see the request")
+	}
+	assertHeader(t, built.Header, "Accept-Charset", "utf-8")
+}
+
+func TestShouldCompressStreamedRequests(t *testing.T) {
+	var built *http.Request
+	c := New(WithGzipRequestForHosts("127.0.0.1"), WithDryRun(func(r *http.Request) {
+		built = r
+	}))
+
+	se, err := c.ExchangeStream("http://127.0.0.1:1/upload", http.MethodPost, strings.NewReader("{\"name\":\"jose\"}"), JSONRequestCallback)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer se.Body.Close()
+
+	if built == nil {
+		t.Fatal("Expected the dry run callback to see the request")
+	}
+	assertHeader(t, built.Header, "Content-Encoding", "gzip")
+}
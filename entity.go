@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+)
+
+// Encoder encodes a value into a request body and reports its content type.
+type Encoder interface {
+	Encode(v interface{}) (io.Reader, string, error)
+}
+
+// JSONEncoder encodes request bodies as application/json.
+type JSONEncoder struct{}
+
+// Encode returns the JSON encoding of v.
+func (JSONEncoder) Encode(v interface{}) (io.Reader, string, error) {
+	w := new(bytes.Buffer)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return nil, "", err
+	}
+	return w, "application/json", nil
+}
+
+// XMLEncoder encodes request bodies as application/xml.
+type XMLEncoder struct{}
+
+// Encode returns the XML encoding of v.
+func (XMLEncoder) Encode(v interface{}) (io.Reader, string, error) {
+	w := new(bytes.Buffer)
+	if err := xml.NewEncoder(w).Encode(v); err != nil {
+		return nil, "", err
+	}
+	return w, "application/xml", nil
+}
+
+// PostEntity encodes v with the given encoder and posts it to the given URL
+func (c *Client) PostEntity(url string, v interface{}, encoder Encoder, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	body, contentType, err := encoder.Encode(v)
+	if err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+	return c.Post(url, body, func(r *http.Request) {
+		if requestCallback != nil {
+			requestCallback(r)
+		}
+		r.Header.Set("Content-Type", contentType)
+	})
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type entityPayload struct {
+	XMLName      xml.Name `json:"-" xml:"payload"`
+	SomeProperty string   `json:"someProperty" xml:"someProperty"`
+}
+
+func TestShouldPostEntityAsJSON(t *testing.T) {
+	c := New()
+	ts := entityTestServer()
+	defer ts.Close()
+
+	re, err := c.PostEntity(ts.URL, &entityPayload{SomeProperty: "someValue"}, JSONEncoder{}, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.Header, "Content-Type", "application/json")
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}\n")
+}
+
+func TestShouldPostEntityAsXML(t *testing.T) {
+	c := New()
+	ts := entityTestServer()
+	defer ts.Close()
+
+	re, err := c.PostEntity(ts.URL, &entityPayload{SomeProperty: "someValue"}, XMLEncoder{}, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.Header, "Content-Type", "application/xml")
+	assertBody(t, re.BodyString(), "<payload><someProperty>someValue</someProperty></payload>")
+}
+
+func entityTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rBody, _ := ioutil.ReadAll(r.Body)
+		w.Header()["Content-Type"] = r.Header["Content-Type"]
+		w.Write(rBody)
+	}))
+}
@@ -0,0 +1,45 @@
+package rest
+
+import "net/http"
+
+// ErrorClass classifies the outcome of a HTTP exchange for the resilience features.
+type ErrorClass int
+
+const (
+	// ErrorClassNone is a successful exchange.
+	ErrorClassNone ErrorClass = iota
+	// ErrorClassRetryable is a failure worth retrying.
+	ErrorClassRetryable
+	// ErrorClassFatal is a failure that won't get better by retrying.
+	ErrorClassFatal
+	// ErrorClassAuth is an authentication or authorization failure.
+	ErrorClassAuth
+)
+
+// WithErrorClassifier replaces the policy deciding what kind of failure an exchange is,
+// so retries share a single classification.
+func WithErrorClassifier(fn func(re *ResponseEntity, err error) ErrorClass) Option {
+	return func(c *Client) {
+		c.classifier = fn
+	}
+}
+
+func (c *Client) classify(re *ResponseEntity, err error) ErrorClass {
+	if c.classifier != nil {
+		return c.classifier(re, err)
+	}
+
+	switch {
+	case err != nil && c.retry.retryable(err):
+		return ErrorClassRetryable
+	case err != nil:
+		return ErrorClassFatal
+	case c.retry.statusCodes[re.StatusCode]:
+		return ErrorClassRetryable
+	case re.StatusCode == http.StatusUnauthorized || re.StatusCode == http.StatusForbidden:
+		return ErrorClassAuth
+	case re.StatusCode >= http.StatusBadRequest:
+		return ErrorClassFatal
+	}
+	return ErrorClassNone
+}
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetryWhenClassifiedRetryable(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusInternalServerError, http.StatusOK)
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithErrorClassifier(testClassifier))
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldNotRetryWhenClassifiedFatal(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusBadRequest, http.StatusOK)
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithErrorClassifier(testClassifier))
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusBadRequest)
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldClassifyByDefault(t *testing.T) {
+	c := New()
+	cases := map[int]ErrorClass{
+		http.StatusOK:                  ErrorClassNone,
+		http.StatusUnauthorized:        ErrorClassAuth,
+		http.StatusNotFound:            ErrorClassFatal,
+		http.StatusInternalServerError: ErrorClassFatal,
+	}
+	for statusCode, expected := range cases {
+		if class := c.classify(&ResponseEntity{StatusCode: statusCode}, nil); class != expected {
+			t.Errorf("Expected class for %v: [%v] got: [%v]", statusCode, expected, class)
+		}
+	}
+}
+
+func testClassifier(re *ResponseEntity, err error) ErrorClass {
+	switch {
+	case err != nil || re.StatusCode >= http.StatusInternalServerError:
+		return ErrorClassRetryable
+	case re.StatusCode >= http.StatusBadRequest:
+		return ErrorClassFatal
+	}
+	return ErrorClassNone
+}
+
+// statusSequenceTestServer answers each request with the next status code, repeating the last one.
+func statusSequenceTestServer(statusCodes ...int) (*httptest.Server, *int32) {
+	attempts := new(int32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(attempts, 1))
+		if n > len(statusCodes) {
+			n = len(statusCodes)
+		}
+		w.WriteHeader(statusCodes[n-1])
+	}))
+	return ts, attempts
+}
@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// RequestError records the method and URL of a failed exchange and the error that caused it.
+type RequestError struct {
+	Op  string
+	URL string
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s %q: %v", e.Op, e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the underlying error is a timeout.
+func (e *RequestError) Timeout() bool {
+	var timeout interface{ Timeout() bool }
+	return errors.As(e.Err, &timeout) && timeout.Timeout()
+}
+
+func wrapRequestError(method, rawURL string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	return &RequestError{Op: method, URL: rawURL, Err: err}
+}
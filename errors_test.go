@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShouldWrapErrorsInRequestError(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := FromContext(ctx).Get(ts.URL, JSONRequestCallback)
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Expected *RequestError got: [%T]", err)
+	}
+
+	if reqErr.Op != http.MethodGet || reqErr.URL != ts.URL {
+		t.Errorf("Expected op and URL: [%v %v] got: [%v %v]", http.MethodGet, ts.URL, reqErr.Op, reqErr.URL)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) || !reqErr.Timeout() {
+		t.Errorf("Expected deadline exceeded got: [%v]", err)
+	}
+}
+
+func TestShouldUnwrapRequestError(t *testing.T) {
+	err := wrapRequestError(http.MethodPost, "http://localhost", ErrDigestMismatch)
+
+	if !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("Expected %v to unwrap to %v", err, ErrDigestMismatch)
+	}
+
+	expected := "POST \"http://localhost\": " + ErrDigestMismatch.Error()
+	if err.Error() != expected {
+		t.Errorf("Expected message: [%v] got: [%v]", expected, err.Error())
+	}
+
+	if wrapRequestError(http.MethodPost, "http://localhost", nil) != nil {
+		t.Error("Expected nil error to stay nil")
+	}
+}
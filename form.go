@@ -0,0 +1,196 @@
+package rest
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EncodeForm flattens the struct or map v into form values. Field names come from `form` struct tags
+// ("-" skips a field, ",omitempty" skips zero values), nested structs and maps use dotted keys
+// (addr.city), slices of scalars repeat their key and other slices are indexed (items[0].name).
+func EncodeForm(v interface{}) (url.Values, error) {
+	return encodeValues(v, "form")
+}
+
+// FormRequestCallback sets the headers of an application/x-www-form-urlencoded request.
+func FormRequestCallback(r *http.Request) {
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+}
+
+// PostForm posts the URL encoded form data to the given URL
+func (c *Client) PostForm(url string, data url.Values, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.Post(url, strings.NewReader(data.Encode()), func(r *http.Request) {
+		FormRequestCallback(r)
+		if requestCallback != nil {
+			requestCallback(r)
+		}
+	})
+}
+
+func encodeValues(v interface{}, tag string) (url.Values, error) {
+	values := make(url.Values)
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return values, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("rest: cannot encode %v as values, expected a struct or map", rv.Type())
+	}
+
+	if err := flattenValue(values, "", rv, tag); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func flattenValue(values url.Values, key string, rv reflect.Value, tag string) error {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.CanInterface() && rv.Type().Implements(textMarshalerType) {
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		values.Add(key, string(text))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return flattenStruct(values, key, rv, tag)
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := flattenValue(values, joinKey(key, fmt.Sprint(iter.Key().Interface())), iter.Value(), tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			values.Add(key, string(rv.Bytes()))
+			return nil
+		}
+		for i := 0; i < rv.Len(); i++ {
+			elemKey := key
+			if !isScalar(rv.Index(i)) {
+				elemKey = fmt.Sprintf("%s[%d]", key, i)
+			}
+			if err := flattenValue(values, elemKey, rv.Index(i), tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	s, err := formatScalar(rv)
+	if err != nil {
+		return err
+	}
+	values.Add(key, s)
+	return nil
+}
+
+func flattenStruct(values url.Values, key string, rv reflect.Value, tag string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" && (!field.Anonymous || indirectType(field.Type).Kind() != reflect.Struct) {
+			continue
+		}
+
+		name, opts := field.Name, ""
+		if tagValue, ok := field.Tag.Lookup(tag); ok {
+			parts := strings.SplitN(tagValue, ",", 2)
+			if parts[0] == "-" {
+				continue
+			}
+			if len(parts[0]) > 0 {
+				name = parts[0]
+			}
+			if len(parts) > 1 {
+				opts = parts[1]
+			}
+		}
+
+		fv := rv.Field(i)
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+
+		if field.Anonymous && len(field.Tag.Get(tag)) == 0 && indirectType(field.Type).Kind() == reflect.Struct {
+			if err := flattenValue(values, key, fv, tag); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := flattenValue(values, joinKey(key, name), fv, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatScalar(rv reflect.Value) (string, error) {
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("rest: cannot encode %v as a value", rv.Type())
+}
+
+func isScalar(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+	if rv.Type().Implements(textMarshalerType) {
+		return true
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func joinKey(prefix, name string) string {
+	if len(prefix) == 0 {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type formAddress struct {
+	Street string `form:"street"`
+	City   string `form:"city"`
+}
+
+type formLine struct {
+	SKU      string `form:"sku"`
+	Quantity int    `form:"qty"`
+}
+
+type formAudit struct {
+	CreatedBy string `form:"createdBy"`
+}
+
+type formOrder struct {
+	formAudit
+	Name     string            `form:"name"`
+	Paid     bool              `form:"paid"`
+	Total    float64           `form:"total"`
+	Note     string            `form:"note,omitempty"`
+	Secret   string            `form:"-"`
+	Address  *formAddress      `form:"addr"`
+	Tags     []string          `form:"tags"`
+	Lines    []formLine        `form:"lines"`
+	Meta     map[string]string `form:"meta"`
+	Deadline time.Time         `form:"deadline"`
+	Untagged int
+}
+
+func TestShouldEncodeNestedForm(t *testing.T) {
+	order := formOrder{
+		formAudit: formAudit{CreatedBy: "jose"},
+		Name:      "order 1",
+		Paid:      true,
+		Total:     10.5,
+		Secret:    "hidden",
+		Address:   &formAddress{Street: "Main St", City: "Porto Alegre"},
+		Tags:      []string{"a", "b"},
+		Lines:     []formLine{{SKU: "x1", Quantity: 2}, {SKU: "y2", Quantity: 1}},
+		Meta:      map[string]string{"source": "web"},
+		Deadline:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Untagged:  7,
+	}
+
+	values, err := EncodeForm(&order)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	expected := url.Values{
+		"createdBy":    {"jose"},
+		"name":         {"order 1"},
+		"paid":         {"true"},
+		"total":        {"10.5"},
+		"addr.street":  {"Main St"},
+		"addr.city":    {"Porto Alegre"},
+		"tags":         {"a", "b"},
+		"lines[0].sku": {"x1"},
+		"lines[0].qty": {"2"},
+		"lines[1].sku": {"y2"},
+		"lines[1].qty": {"1"},
+		"meta.source":  {"web"},
+		"deadline":     {"2020-01-02T03:04:05Z"},
+		"Untagged":     {"7"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected values: [%v] got: [%v]", expected, values)
+	}
+}
+
+func TestShouldEncodeFormFromMap(t *testing.T) {
+	values, err := EncodeForm(map[string]interface{}{
+		"q":      "rest client",
+		"page":   2,
+		"filter": map[string][]int{"ids": {1, 2}},
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	expected := url.Values{"q": {"rest client"}, "page": {"2"}, "filter.ids": {"1", "2"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected values: [%v] got: [%v]", expected, values)
+	}
+
+	if _, err := EncodeForm("not a struct"); err == nil {
+		t.Error("Expected error encoding a string")
+	}
+}
+
+func TestShouldPostForm(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+		r.ParseForm()
+		w.Write([]byte(r.PostForm.Get("name") + "|" + strings.Join(r.PostForm["tags"], ",")))
+	}))
+	defer ts.Close()
+
+	re, err := New().PostForm(ts.URL, url.Values{"name": {"jose & co"}, "tags": {"a", "b"}}, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertHeader(t, re.Header, "X-Content-Type", "application/x-www-form-urlencoded")
+	assertBody(t, re.BodyString(), "jose & co|a,b")
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Multipart builds a multipart/form-data body of fields and files, streamed as it's sent rather than
+// buffered. Files from paths are only opened then, so their errors fail the request.
+type Multipart struct {
+	boundary string
+	parts    []formPart
+}
+
+type formPart struct {
+	field    string
+	value    string
+	filename string
+	path     string
+	r        io.Reader
+}
+
+// NewMultipart returns an empty Multipart with a random boundary.
+func NewMultipart() *Multipart {
+	return &Multipart{boundary: multipart.NewWriter(ioutil.Discard).Boundary()}
+}
+
+// Field adds a form field.
+func (m *Multipart) Field(name, value string) *Multipart {
+	m.parts = append(m.parts, formPart{field: name, value: value})
+	return m
+}
+
+// File adds a file field named filename with the content read from r.
+func (m *Multipart) File(field, filename string, r io.Reader) *Multipart {
+	m.parts = append(m.parts, formPart{field: field, filename: filename, r: r})
+	return m
+}
+
+// FileFromPath adds a file field with the content of the file at path, named after its base name.
+func (m *Multipart) FileFromPath(field, path string) *Multipart {
+	m.parts = append(m.parts, formPart{field: field, filename: filepath.Base(path), path: path})
+	return m
+}
+
+// ContentType returns the multipart/form-data content type with the boundary of the body.
+func (m *Multipart) ContentType() string {
+	return "multipart/form-data; boundary=" + m.boundary
+}
+
+// Reader returns the body, written on demand as it's read. It must only be read once.
+func (m *Multipart) Reader() io.ReadCloser {
+	return &multipartReader{m: m}
+}
+
+// PostMultipart posts the multipart body to the given URL
+func (c *Client) PostMultipart(url string, m *Multipart, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.Post(url, m.Reader(), m.callback(requestCallback))
+}
+
+// PutMultipart puts the multipart body to the given URL
+func (c *Client) PutMultipart(url string, m *Multipart, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.Put(url, m.Reader(), m.callback(requestCallback))
+}
+
+func (m *Multipart) callback(requestCallback func(r *http.Request)) func(r *http.Request) {
+	return func(r *http.Request) {
+		if requestCallback != nil {
+			requestCallback(r)
+		}
+		r.Header.Set("Content-Type", m.ContentType())
+	}
+}
+
+func (m *Multipart) writeTo(w io.Writer) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(m.boundary); err != nil {
+		return err
+	}
+	for _, part := range m.parts {
+		if len(part.filename) == 0 {
+			if err := mw.WriteField(part.field, part.value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pw, err := mw.CreateFormFile(part.field, part.filename)
+		if err != nil {
+			return err
+		}
+		r := part.r
+		if len(part.path) > 0 {
+			f, err := os.Open(part.path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		if _, err := io.Copy(pw, r); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// multipartReader pipes the body written by Multipart, starting to write on the first read so nothing is
+// left blocked writing for a body that's never read.
+type multipartReader struct {
+	m    *Multipart
+	once sync.Once
+	pr   *io.PipeReader
+}
+
+func (r *multipartReader) Read(p []byte) (int, error) {
+	r.once.Do(func() {
+		pr, pw := io.Pipe()
+		r.pr = pr
+		go func() {
+			pw.CloseWithError(r.m.writeTo(pw))
+		}()
+	})
+	return r.pr.Read(p)
+}
+
+func (r *multipartReader) Close() error {
+	r.once.Do(func() {})
+	if r.pr == nil {
+		return nil
+	}
+	return r.pr.Close()
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShouldPostMultipart(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		report, header, _ := r.FormFile("report")
+		reportBody, _ := ioutil.ReadAll(report)
+		notes, _, _ := r.FormFile("notes")
+		notesBody, _ := ioutil.ReadAll(notes)
+		w.Write([]byte(r.FormValue("name") + "|" + header.Filename + ":" + string(reportBody) + "|" + string(notesBody)))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "report.csv")
+	ioutil.WriteFile(path, []byte("a,b\n1,2\n"), 0600)
+
+	m := NewMultipart().Field("name", "jose").FileFromPath("report", path).File("notes", "notes.txt", strings.NewReader("hello"))
+	re, err := New().PostMultipart(ts.URL, m, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertHeader(t, re.SentHeaders, "Content-Type", m.ContentType())
+	assertBody(t, re.BodyString(), "jose|report.csv:a,b\n1,2\n|hello")
+}
+
+func TestShouldFailMultipartWithMissingFile(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	m := NewMultipart().FileFromPath("report", filepath.Join(t.TempDir(), "missing.csv"))
+	if _, err := New().PutMultipart(ts.URL, m, nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected error: [%v] got: [%v]", os.ErrNotExist, err)
+	}
+}
@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// HALLink struct represents a link object of a HAL document.
+type HALLink struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// HALDocument struct represents an application/hal+json resource. A relation with a single link or
+// embedded resource is held like one with an array of them.
+type HALDocument struct {
+	Links    map[string][]HALLink
+	Embedded map[string][]json.RawMessage
+	body     []byte
+}
+
+// ParseHAL parses the _links and _embedded sections of the HAL document b.
+func ParseHAL(b []byte) (*HALDocument, error) {
+	var sections struct {
+		Links    map[string]json.RawMessage `json:"_links"`
+		Embedded map[string]json.RawMessage `json:"_embedded"`
+	}
+	if err := json.Unmarshal(b, &sections); err != nil {
+		return nil, err
+	}
+
+	d := &HALDocument{Links: make(map[string][]HALLink), Embedded: make(map[string][]json.RawMessage), body: b}
+	for rel, raw := range sections.Links {
+		var links []HALLink
+		if err := unmarshalOneOrMany(raw, &links); err != nil {
+			return nil, err
+		}
+		d.Links[rel] = links
+	}
+	for rel, raw := range sections.Embedded {
+		var resources []json.RawMessage
+		if err := unmarshalOneOrMany(raw, &resources); err != nil {
+			return nil, err
+		}
+		d.Embedded[rel] = resources
+	}
+	return d, nil
+}
+
+// HAL parses the body as a HAL document.
+func (re *ResponseEntity) HAL() (*HALDocument, error) {
+	return ParseHAL(re.Body)
+}
+
+// Link returns the first link of the relation.
+func (d *HALDocument) Link(rel string) (HALLink, bool) {
+	if links := d.Links[rel]; len(links) > 0 {
+		return links[0], true
+	}
+	return HALLink{}, false
+}
+
+// Decode decodes the properties of the resource itself into the value pointed to by v.
+func (d *HALDocument) Decode(v interface{}) error {
+	return json.Unmarshal(d.body, v)
+}
+
+// EmbeddedDocuments returns the resources embedded for the relation as HAL documents, to follow their
+// own links and embedded resources.
+func (d *HALDocument) EmbeddedDocuments(rel string) ([]*HALDocument, error) {
+	var documents []*HALDocument
+	for _, raw := range d.Embedded[rel] {
+		document, err := ParseHAL(raw)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// HALEmbedded decodes the resources embedded in d for the relation into Ts.
+func HALEmbedded[T any](d *HALDocument, rel string) ([]T, error) {
+	var resources []T
+	for _, raw := range d.Embedded[rel] {
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		resources = append(resources, v)
+	}
+	return resources, nil
+}
+
+// unmarshalOneOrMany decodes the JSON array, or the single value, raw into the slice pointed to by v.
+func unmarshalOneOrMany[T any](raw json.RawMessage, v *[]T) error {
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, v)
+	}
+	var one T
+	if err := json.Unmarshal(raw, &one); err != nil {
+		return err
+	}
+	*v = []T{one}
+	return nil
+}
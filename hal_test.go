@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const halOrders = `{
+	"_links": {
+		"self": {"href": "/orders"},
+		"find": {"href": "/orders{?id}", "templated": true},
+		"curies": [{"name": "acme", "href": "https://docs.acme.com/{rel}", "templated": true}]
+	},
+	"currentlyProcessing": 14,
+	"_embedded": {
+		"orders": [
+			{"_links": {"self": {"href": "/orders/123"}}, "total": 30.00, "status": "shipped"},
+			{"_links": {"self": {"href": "/orders/124"}}, "total": 20.00, "status": "processing"}
+		],
+		"customer": {"_links": {"self": {"href": "/customers/7"}}, "name": "Jose"}
+	}
+}`
+
+type halOrder struct {
+	Total  float64 `json:"total"`
+	Status string  `json:"status"`
+}
+
+func TestShouldParseHALDocument(t *testing.T) {
+	re := ResponseEntity{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"application/hal+json"}}, Body: []byte(halOrders)}
+	d, err := re.HAL()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if link, ok := d.Link("self"); !ok || link.Href != "/orders" {
+		t.Errorf("Expected self link: [/orders] got: [%v]", link)
+	}
+	if link, _ := d.Link("find"); !link.Templated {
+		t.Errorf("Expected templated find link got: [%v]", link)
+	}
+	if curies := d.Links["curies"]; len(curies) != 1 || curies[0].Name != "acme" {
+		t.Errorf("Expected acme curie got: [%v]", curies)
+	}
+	if _, ok := d.Link("next"); ok {
+		t.Error("Expected no next link")
+	}
+
+	var resource struct {
+		CurrentlyProcessing int `json:"currentlyProcessing"`
+	}
+	if err := d.Decode(&resource); err != nil || resource.CurrentlyProcessing != 14 {
+		t.Errorf("Expected currentlyProcessing: [14] got: [%v] [%v]", resource.CurrentlyProcessing, err)
+	}
+
+	orders, err := HALEmbedded[halOrder](d, "orders")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	expected := []halOrder{{Total: 30, Status: "shipped"}, {Total: 20, Status: "processing"}}
+	if !reflect.DeepEqual(orders, expected) {
+		t.Errorf("Expected orders: [%v] got: [%v]", expected, orders)
+	}
+
+	customers, err := d.EmbeddedDocuments("customer")
+	if err != nil || len(customers) != 1 {
+		t.Fatalf("Expected a customer got: [%v] [%v]", customers, err)
+	}
+	if link, _ := customers[0].Link("self"); link.Href != "/customers/7" {
+		t.Errorf("Expected customer link: [/customers/7] got: [%v]", link.Href)
+	}
+}
@@ -0,0 +1,36 @@
+package rest
+
+import "net/http"
+
+// WithDefaultHeader sends the header with every request. It's set ahead of the request callback, which
+// can still override it.
+func WithDefaultHeader(name, value string) Option {
+	return func(c *Client) {
+		c.setDefaultHeader(name, value)
+	}
+}
+
+// WithAcceptCharset sends an Accept-Charset header with every request.
+func WithAcceptCharset(charset string) Option {
+	return func(c *Client) {
+		c.setDefaultHeader("Accept-Charset", charset)
+	}
+}
+
+// setDefaultHeader sets a header sent with every request, copying the headers so clients derived
+// through With don't affect each other.
+func (c *Client) setDefaultHeader(name, value string) {
+	header := c.header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set(name, value)
+	c.header = header
+}
+
+// applyDefaultHeaders sets the client default headers on req, ahead of the request callback.
+func (c *Client) applyDefaultHeaders(req *http.Request) {
+	for name, values := range c.header {
+		req.Header[name] = append([]string(nil), values...)
+	}
+}
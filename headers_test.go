@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldSendAcceptCharset(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Accept-Charset", r.Header.Get("Accept-Charset"))
+	}))
+	defer ts.Close()
+
+	c := New(WithAcceptCharset("iso-8859-1"))
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, re.Header, "X-Accept-Charset", "iso-8859-1")
+
+	re, err = c.With(WithAcceptCharset("utf-8")).Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, re.Header, "X-Accept-Charset", "utf-8")
+
+	re, _ = c.Get(ts.URL, func(r *http.Request) {
+		r.Header.Set("Accept-Charset", "utf-16")
+	})
+	assertHeader(t, re.Header, "X-Accept-Charset", "utf-16")
+
+	if c.header.Get("Accept-Charset") != "iso-8859-1" {
+		t.Errorf("Derived client should not change the original: [%v]", c.header)
+	}
+}
+
+func TestShouldSendDefaultHeaders(t *testing.T) {
+	var sent http.Header
+	c := New(WithDefaultHeader("X-Api-Version", "2"), WithDefaultHeader("X-Client", "rest"), WithDryRun(func(r *http.Request) {
+		sent = r.Header
+	}))
+
+	if _, err := c.Get("http://api.example.com/users", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, sent, "X-Api-Version", "2")
+	assertHeader(t, sent, "X-Client", "rest")
+
+	c.Get("http://api.example.com/users", func(r *http.Request) {
+		r.Header.Set("X-Api-Version", "3")
+	})
+	assertHeader(t, sent, "X-Api-Version", "3")
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HMACSigner configures the signing of requests with a shared secret by HMACSigningMiddleware.
+type HMACSigner struct {
+	Secret []byte
+	// Hash is the hash function of both the HMAC and the body digest, sha256.New when nil.
+	Hash func() hash.Hash
+	// SignatureHeader is the header of the hex signature, X-Signature when empty.
+	SignatureHeader string
+	// TimestampHeader is the header of the signed Unix timestamp, X-Timestamp when empty.
+	TimestampHeader string
+}
+
+// HMACSigningMiddleware signs every request sent with an HMAC of its method, path with query, hex body digest
+// and Unix timestamp, one per line, setting the signature and timestamp headers.
+func HMACSigningMiddleware(signer HMACSigner) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if err := signer.sign(req, time.Now()); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}
+	}
+}
+
+func (s HMACSigner) sign(req *http.Request, now time.Time) error {
+	newHash, signatureHeader, timestampHeader := s.Hash, s.SignatureHeader, s.TimestampHeader
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	if len(signatureHeader) == 0 {
+		signatureHeader = "X-Signature"
+	}
+	if len(timestampHeader) == 0 {
+		timestampHeader = "X-Timestamp"
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return err
+	}
+	digest := newHash()
+	digest.Write(body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	mac := hmac.New(newHash, s.Secret)
+	mac.Write([]byte(req.Method + "\n" + req.URL.RequestURI() + "\n" + hex.EncodeToString(digest.Sum(nil)) + "\n" + timestamp))
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(signatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShouldSignHMAC(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://partner.example.com/orders?dry=true", strings.NewReader("{}"))
+	if err := (HMACSigner{Secret: []byte("secret")}).sign(req, time.Unix(1600000000, 0)); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	bodyDigest := sha256.Sum256([]byte("{}"))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("POST\n/orders?dry=true\n" + hex.EncodeToString(bodyDigest[:]) + "\n1600000000"))
+
+	assertHeader(t, req.Header, "X-Timestamp", "1600000000")
+	assertHeader(t, req.Header, "X-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+	if body, _ := ioutil.ReadAll(req.Body); string(body) != "{}" {
+		t.Errorf("Expected body to stay readable got: [%v]", string(body))
+	}
+}
+
+func TestShouldSignRequestsWithHMACMiddleware(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodyDigest := sha512.Sum512(body)
+		mac := hmac.New(sha512.New, []byte("secret"))
+		mac.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n" + hex.EncodeToString(bodyDigest[:]) + "\n" + r.Header.Get("X-Partner-Time")))
+		if !hmac.Equal([]byte(r.Header.Get("X-Partner-Signature")), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	c := New(WithMiddleware(HMACSigningMiddleware(HMACSigner{
+		Secret:          []byte("secret"),
+		Hash:            sha512.New,
+		SignatureHeader: "X-Partner-Signature",
+		TimestampHeader: "X-Partner-Time",
+	})))
+	re, err := c.Post(ts.URL+"/orders", strings.NewReader("{\"id\":1}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+}
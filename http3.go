@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+)
+
+// WithHTTP3 sends requests over HTTP/3 through the round tripper newRoundTripper returns, a QUIC round tripper
+// such as the http3.Transport of github.com/quic-go/quic-go, which this package doesn't depend on. It's given
+// the TLS configuration built from the Client's options (nil when there is none), which it must use for the
+// QUIC handshake. This is experimental: idempotent requests it fails are sent again over the built transport,
+// attempting HTTP/2 as with WithHTTP2, as long as their body can be replayed.
+func WithHTTP3(newRoundTripper func(tlsConfig *tls.Config) http.RoundTripper) Option {
+	return func(c *Client) {
+		c.http3 = newRoundTripper
+		c.http2 = true
+	}
+}
+
+type http3RoundTripper struct {
+	http3    http.RoundTripper
+	fallback *http.Transport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *http3RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := rt.http3.RoundTrip(req)
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return res, err
+	}
+
+	// the server may have processed a non-idempotent request before the QUIC connection failed
+	if !idempotent(req.Method) {
+		return nil, err
+	}
+	next, ok := rewindRequest(req)
+	if !ok {
+		return nil, err
+	}
+	return rt.fallback.RoundTrip(next)
+}
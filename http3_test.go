@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShouldSendOverHTTP3(t *testing.T) {
+	h3 := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{Proto: "HTTP/3.0", ProtoMajor: 3, StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody}, nil
+	})
+
+	re, err := New(WithHTTP3(http3Transport(h3))).Get("https://api.example.com/users", JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if re.Proto != "HTTP/3.0" || re.ProtocolDowngraded {
+		t.Errorf("Expected protocol: [%v] got: [%v] downgraded: [%v]", "HTTP/3.0", re.Proto, re.ProtocolDowngraded)
+	}
+}
+
+func TestShouldFallBackFromHTTP3(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	var h3Body string
+	h3 := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := ioutil.ReadAll(req.Body)
+		h3Body = string(b)
+		return nil, errors.New("quic: no recent network activity")
+	})
+
+	re, err := New(WithHTTP3(http3Transport(h3))).Put(ts.URL, strings.NewReader("{}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "{}")
+	if h3Body != "{}" {
+		t.Errorf("Expected HTTP/3 attempt with body got: [%v]", h3Body)
+	}
+}
+
+func TestShouldNotFallBackFromHTTP3ForNonIdempotentRequests(t *testing.T) {
+	ts, requests := statusSequenceTestServer(http.StatusOK)
+	defer ts.Close()
+
+	h3 := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("quic: no recent network activity")
+	})
+
+	if _, err := New(WithHTTP3(http3Transport(h3))).Post(ts.URL, strings.NewReader("{}"), JSONRequestCallback); err == nil {
+		t.Error("Expected the HTTP/3 error")
+	}
+	if n := atomic.LoadInt32(requests); n != 0 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 0, n)
+	}
+}
+
+func TestShouldPassTLSConfigToHTTP3(t *testing.T) {
+	var tlsConfig *tls.Config
+	h3 := func(cfg *tls.Config) http.RoundTripper {
+		tlsConfig = cfg
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody}, nil
+		})
+	}
+
+	if _, err := New(WithHTTP3(h3), WithInsecureTLS()).Get("https://api.example.com/users", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if tlsConfig == nil || !tlsConfig.InsecureSkipVerify {
+		t.Errorf("Expected the TLS config to reach the HTTP/3 round tripper got: [%v]", tlsConfig)
+	}
+}
+
+func http3Transport(rt http.RoundTripper) func(*tls.Config) http.RoundTripper {
+	return func(*tls.Config) http.RoundTripper {
+		return rt
+	}
+}
@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// IdempotencyKeyStore persists the Idempotency-Key of a logical operation, named with
+// ContextWithIdempotentOperation, until the operation definitively succeeds or fails.
+type IdempotencyKeyStore interface {
+	Load(operation string) (string, bool, error)
+	Store(operation, key string) error
+	Delete(operation string) error
+}
+
+// WithIdempotencyKeyStore sends an Idempotency-Key header on POST and PATCH requests. The key is kept
+// across retries of a call; when the request context names its operation it's also kept in s and reused
+// across calls until the server gives a definitive (non 5xx) answer.
+func WithIdempotencyKeyStore(s IdempotencyKeyStore) Option {
+	return func(c *Client) {
+		c.idempotencyKeys = s
+	}
+}
+
+type idempotentOperationKey struct{}
+
+// ContextWithIdempotentOperation returns a copy of ctx naming the logical operation its requests belong
+// to, so that calls for the same operation reuse the stored Idempotency-Key (see FromContext).
+func ContextWithIdempotentOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, idempotentOperationKey{}, operation)
+}
+
+type memoryIdempotencyKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+// NewMemoryIdempotencyKeyStore returns an IdempotencyKeyStore that keeps keys in memory.
+func NewMemoryIdempotencyKeyStore() IdempotencyKeyStore {
+	return &memoryIdempotencyKeyStore{keys: make(map[string]string)}
+}
+
+func (s *memoryIdempotencyKeyStore) Load(operation string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[operation]
+	return key, ok, nil
+}
+
+func (s *memoryIdempotencyKeyStore) Store(operation, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[operation] = key
+	return nil
+}
+
+func (s *memoryIdempotencyKeyStore) Delete(operation string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, operation)
+	return nil
+}
+
+// applyIdempotencyKey sets the Idempotency-Key header of req and returns the operation it belongs to.
+func (c *Client) applyIdempotencyKey(req *http.Request) (string, error) {
+	if c.idempotencyKeys == nil || (req.Method != http.MethodPost && req.Method != http.MethodPatch) {
+		return "", nil
+	}
+	if len(req.Header.Get("Idempotency-Key")) > 0 {
+		return "", nil
+	}
+
+	operation, _ := req.Context().Value(idempotentOperationKey{}).(string)
+	if len(operation) == 0 {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Idempotency-Key", key)
+		return "", nil
+	}
+
+	key, ok, err := c.idempotencyKeys.Load(operation)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if key, err = newIdempotencyKey(); err != nil {
+			return "", err
+		}
+		if err := c.idempotencyKeys.Store(operation, key); err != nil {
+			return "", err
+		}
+	}
+	req.Header.Set("Idempotency-Key", key)
+	return operation, nil
+}
+
+// releaseIdempotencyKey forgets the key of operation once the server answered definitively.
+func (c *Client) releaseIdempotencyKey(operation string, re ResponseEntity, err error) error {
+	if len(operation) == 0 || err != nil || re.StatusCode >= http.StatusInternalServerError {
+		return nil
+	}
+	return c.idempotencyKeys.Delete(operation)
+}
+
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
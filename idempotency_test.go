@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShouldReuseIdempotencyKeyUntilDefinitiveAnswer(t *testing.T) {
+	ts, keys := idempotencyTestServer(http.StatusServiceUnavailable, http.StatusCreated, http.StatusCreated)
+	defer ts.Close()
+
+	store := NewMemoryIdempotencyKeyStore()
+	c := FromContext(ContextWithIdempotentOperation(context.Background(), "charge-42"), WithIdempotencyKeyStore(store))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Post(ts.URL, strings.NewReader("{\"amount\":42}"), JSONRequestCallback); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	}
+
+	received := keys()
+	if len(received[0]) == 0 || received[0] != received[1] {
+		t.Errorf("Expected the key to survive a 5xx got: [%v]", received)
+	}
+
+	if received[2] == received[1] {
+		t.Errorf("Expected a new key after success got: [%v]", received)
+	}
+
+	if _, ok, _ := store.Load("charge-42"); ok {
+		t.Error("Expected the key to be released after success")
+	}
+}
+
+func TestShouldNotShareIdempotencyKeyBetweenUnnamedOperations(t *testing.T) {
+	ts, keys := idempotencyTestServer(http.StatusServiceUnavailable, http.StatusCreated)
+	defer ts.Close()
+
+	c := New(WithIdempotencyKeyStore(NewMemoryIdempotencyKeyStore()))
+	c.Post(ts.URL, strings.NewReader("{\"amount\":42}"), JSONRequestCallback)
+	c.Post(ts.URL, strings.NewReader("{\"amount\":7}"), JSONRequestCallback)
+
+	received := keys()
+	if len(received[0]) == 0 || len(received[1]) == 0 || received[0] == received[1] {
+		t.Errorf("Expected distinct keys for distinct operations got: [%v]", received)
+	}
+}
+
+func TestShouldKeepIdempotencyKeyAcrossRetries(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Get("Idempotency-Key"))
+		first := len(received) == 1
+		mu.Unlock()
+		if first {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		}
+	}))
+	defer ts.Close()
+
+	c := New(WithIdempotencyKeyStore(NewMemoryIdempotencyKeyStore()), WithRetry(3, 10*time.Millisecond), WithRetryOnErrorContaining("EOF"))
+	if _, err := c.Post(ts.URL, strings.NewReader("{\"amount\":42}"), JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0] != received[1] {
+		t.Errorf("Expected the same key on both attempts got: [%v]", received)
+	}
+}
+
+func idempotencyTestServer(statusCodes ...int) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Get("Idempotency-Key"))
+		statusCode := statusCodes[len(received)-1]
+		mu.Unlock()
+		w.WriteHeader(statusCode)
+	}))
+	return ts, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
@@ -0,0 +1,42 @@
+package rest
+
+import "time"
+
+// RequestMetrics struct represents a completed exchange, as reported to the metrics hook.
+type RequestMetrics struct {
+	Operation  string
+	Method     string
+	URL        string
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// WithMetricsHook calls fn once every exchange completed, retries included.
+func WithMetricsHook(fn func(m RequestMetrics)) Option {
+	return func(c *Client) {
+		c.metricsHook = fn
+	}
+}
+
+// WithOperationName tags requests with a logical operation name, passed to the metrics hook as a low
+// cardinality label instead of the raw URL. It's usually applied per call through Client.With.
+func WithOperationName(name string) Option {
+	return func(c *Client) {
+		c.operation = name
+	}
+}
+
+func (c *Client) reportMetrics(method, url string, start time.Time, re ResponseEntity, err error) {
+	if c.metricsHook == nil {
+		return
+	}
+	c.metricsHook(RequestMetrics{
+		Operation:  c.operation,
+		Method:     method,
+		URL:        url,
+		StatusCode: re.StatusCode,
+		Duration:   time.Since(start),
+		Err:        err,
+	})
+}
@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestShouldReportOperationNameToMetricsHook(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	var reported []RequestMetrics
+	c := New(WithMetricsHook(func(m RequestMetrics) {
+		reported = append(reported, m)
+	}))
+
+	if _, err := c.With(WithOperationName("list_users")).Get(ts.URL+"/users?page=2", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if _, err := c.Get(ts.URL+"/users/42", JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(reported) != 2 {
+		t.Fatalf("Expected 2 reported metrics got: [%v]", len(reported))
+	}
+
+	first := reported[0]
+	if first.Operation != "list_users" || first.Method != http.MethodGet || first.StatusCode != http.StatusOK || first.Duration <= 0 {
+		t.Errorf("Unexpected metrics: [%+v]", first)
+	}
+
+	if reported[1].Operation != "" || reported[1].URL != ts.URL+"/users/42" {
+		t.Errorf("Operation name should only apply to the derived client: [%+v]", reported[1])
+	}
+}
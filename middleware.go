@@ -0,0 +1,38 @@
+package rest
+
+import "net/http"
+
+// RoundTripFunc is a function sending a single HTTP request, and an http.RoundTripper.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (fn RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// Middleware wraps the sending of a request, e.g. to add auth, logging or metrics around next.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware wraps every request sent, attempt and redirect alike, with the given middleware. The first
+// middleware is the outermost, seeing the request first and the response last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(append([]Middleware(nil), c.middleware...), middleware...)
+	}
+}
+
+// chain wraps the transport with the Client's middleware.
+func (c *Client) chain(transport http.RoundTripper) http.RoundTripper {
+	if len(c.middleware) == 0 {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	next := RoundTripFunc(transport.RoundTrip)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestShouldChainMiddleware(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				req.Header.Add("X-Middleware", name)
+				res, err := next(req)
+				calls = append(calls, name+" response")
+				return res, err
+			}
+		}
+	}
+
+	c := New(WithMiddleware(trace("auth")), WithMiddleware(trace("logging")))
+	re, err := c.Post(ts.URL, strings.NewReader("{}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), "{}")
+	expected := "auth request,logging request,logging response,auth response"
+	if strings.Join(calls, ",") != expected {
+		t.Errorf("Expected calls: [%v] got: [%v]", expected, strings.Join(calls, ","))
+	}
+}
+
+func TestShouldShortCircuitInMiddleware(t *testing.T) {
+	c := New(WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusTeapot, Header: make(http.Header), Body: http.NoBody}, nil
+		}
+	}))
+
+	re, err := c.Get("http://api.example.com/users", JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusTeapot)
+}
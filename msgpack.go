@@ -0,0 +1,857 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MessagePackCodec encodes and decodes application/msgpack bodies with reflection. Struct fields are named
+// by their msgpack tag, or json tag without one, honouring "-" and omitempty. Byte slices and arrays encode
+// as bin, time.Time as the -1 timestamp extension, and other extension types decode as MessagePackExtension.
+// Values decoded into an interface{} are nil, bool, int64, uint64, float64, string, []byte,
+// time.Time, MessagePackExtension, []interface{} and map[string]interface{}.
+var MessagePackCodec = Codec{Marshal: marshalMessagePack, Unmarshal: unmarshalMessagePack}
+
+// MessagePackExtension is a MessagePack extension value, other than the timestamp.
+type MessagePackExtension struct {
+	Type int8
+	Data []byte
+}
+
+func init() {
+	RegisterCodec("application/msgpack", MessagePackCodec)
+	RegisterCodec("application/x-msgpack", MessagePackCodec)
+}
+
+// MessagePackRequestCallback sets the headers of a request exchanging application/msgpack.
+func MessagePackRequestCallback(r *http.Request) {
+	r.Header.Add("Accept", "application/msgpack")
+	r.Header.Add("Content-Type", "application/msgpack")
+	r.Header.Add("Cache-Control", "no-cache")
+}
+
+// EncodeMessagePack returns the MessagePack encoding of v in a reader
+func EncodeMessagePack(v interface{}) (io.Reader, error) {
+	b, err := marshalMessagePack(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// DecodeMessagePack decodes the MessagePack encoded b into the value pointed to by v.
+func DecodeMessagePack(b []byte, v interface{}) error {
+	return unmarshalMessagePack(b, v)
+}
+
+// maxMessagePackDepth bounds the nesting of encoded and decoded arrays and maps, as encoding/json does.
+const maxMessagePackDepth = 10000
+
+var (
+	messagePackTimeType      = reflect.TypeOf(time.Time{})
+	messagePackExtensionType = reflect.TypeOf(MessagePackExtension{})
+)
+
+func marshalMessagePack(v interface{}) ([]byte, error) {
+	w := new(bytes.Buffer)
+	if err := writeMessagePack(w, reflect.ValueOf(v), 0); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func unmarshalMessagePack(b []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("rest: cannot decode MessagePack into %T", v)
+	}
+
+	r := bytes.NewReader(b)
+	value, err := readMessagePack(r, 0)
+	if err != nil {
+		return err
+	}
+	if r.Len() > 0 {
+		return errors.New("rest: trailing data after MessagePack value")
+	}
+	return assignMessagePack(rv.Elem(), value)
+}
+
+// writeMessagePack writes v, depth bounding the nesting of cyclic values.
+func writeMessagePack(w *bytes.Buffer, v reflect.Value, depth int) error {
+	if depth >= maxMessagePackDepth {
+		return errMessagePackDepth
+	}
+	if !v.IsValid() {
+		w.WriteByte(0xc0)
+		return nil
+	}
+	if v.CanInterface() {
+		switch value := v.Interface().(type) {
+		case time.Time:
+			writeMessagePackTime(w, value)
+			return nil
+		case MessagePackExtension:
+			writeMessagePackExtension(w, value.Type, value.Data)
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			w.WriteByte(0xc3)
+		} else {
+			w.WriteByte(0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeMessagePackInt(w, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeMessagePackUint(w, v.Uint())
+	case reflect.Float32:
+		writeMessagePackHeader(w, 0xca, float32(v.Float()))
+	case reflect.Float64:
+		writeMessagePackHeader(w, 0xcb, v.Float())
+	case reflect.String:
+		writeMessagePackString(w, v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			w.WriteByte(0xc0)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			writeMessagePackBin(w, v.Bytes())
+			return nil
+		}
+		return writeMessagePackArray(w, v, depth)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			writeMessagePackBin(w, b)
+			return nil
+		}
+		return writeMessagePackArray(w, v, depth)
+	case reflect.Map:
+		if v.IsNil() {
+			w.WriteByte(0xc0)
+			return nil
+		}
+		keys := v.MapKeys()
+		if v.Type().Key().Kind() == reflect.String {
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		}
+		writeMessagePackLength(w, len(keys), 0x80, 0xde, 0xdf)
+		for _, key := range keys {
+			if err := writeMessagePack(w, key, depth+1); err != nil {
+				return err
+			}
+			if err := writeMessagePack(w, v.MapIndex(key), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		var fields []messagePackField
+		var values []reflect.Value
+		for _, field := range messagePackFields(v.Type()) {
+			fv, ok := messagePackFieldValue(v, field.index)
+			if !ok || (field.omitEmpty && isEmptyMessagePackValue(fv)) {
+				continue
+			}
+			fields = append(fields, field)
+			values = append(values, fv)
+		}
+		writeMessagePackLength(w, len(fields), 0x80, 0xde, 0xdf)
+		for i, field := range fields {
+			writeMessagePackString(w, field.name)
+			if err := writeMessagePack(w, values[i], depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			w.WriteByte(0xc0)
+			return nil
+		}
+		return writeMessagePack(w, v.Elem(), depth+1)
+	default:
+		return fmt.Errorf("rest: cannot encode %s as MessagePack", v.Type())
+	}
+	return nil
+}
+
+func writeMessagePackArray(w *bytes.Buffer, v reflect.Value, depth int) error {
+	writeMessagePackLength(w, v.Len(), 0x90, 0xdc, 0xdd)
+	for i := 0; i < v.Len(); i++ {
+		if err := writeMessagePack(w, v.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMessagePackString(w *bytes.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		w.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		w.Write([]byte{0xd9, byte(n)})
+	case n <= math.MaxUint16:
+		writeMessagePackHeader(w, 0xda, uint16(n))
+	default:
+		writeMessagePackHeader(w, 0xdb, uint32(n))
+	}
+	w.WriteString(s)
+}
+
+func writeMessagePackBin(w *bytes.Buffer, b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		w.Write([]byte{0xc4, byte(n)})
+	case n <= math.MaxUint16:
+		writeMessagePackHeader(w, 0xc5, uint16(n))
+	default:
+		writeMessagePackHeader(w, 0xc6, uint32(n))
+	}
+	w.Write(b)
+}
+
+func writeMessagePackInt(w *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0:
+		writeMessagePackUint(w, uint64(i))
+	case i >= -32:
+		w.WriteByte(byte(i))
+	case i >= math.MinInt8:
+		w.Write([]byte{0xd0, byte(i)})
+	case i >= math.MinInt16:
+		writeMessagePackHeader(w, 0xd1, int16(i))
+	case i >= math.MinInt32:
+		writeMessagePackHeader(w, 0xd2, int32(i))
+	default:
+		writeMessagePackHeader(w, 0xd3, i)
+	}
+}
+
+func writeMessagePackUint(w *bytes.Buffer, u uint64) {
+	switch {
+	case u <= math.MaxInt8:
+		w.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		w.Write([]byte{0xcc, byte(u)})
+	case u <= math.MaxUint16:
+		writeMessagePackHeader(w, 0xcd, uint16(u))
+	case u <= math.MaxUint32:
+		writeMessagePackHeader(w, 0xce, uint32(u))
+	default:
+		writeMessagePackHeader(w, 0xcf, u)
+	}
+}
+
+func writeMessagePackLength(w *bytes.Buffer, n int, fix, format16, format32 byte) {
+	switch {
+	case n < 16:
+		w.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		writeMessagePackHeader(w, format16, uint16(n))
+	default:
+		writeMessagePackHeader(w, format32, uint32(n))
+	}
+}
+
+func writeMessagePackHeader(w *bytes.Buffer, format byte, data interface{}) {
+	w.WriteByte(format)
+	binary.Write(w, binary.BigEndian, data)
+}
+
+func writeMessagePackExtension(w *bytes.Buffer, typ int8, data []byte) {
+	switch n := len(data); {
+	case n == 1:
+		w.WriteByte(0xd4)
+	case n == 2:
+		w.WriteByte(0xd5)
+	case n == 4:
+		w.WriteByte(0xd6)
+	case n == 8:
+		w.WriteByte(0xd7)
+	case n == 16:
+		w.WriteByte(0xd8)
+	case n <= math.MaxUint8:
+		w.Write([]byte{0xc7, byte(n)})
+	case n <= math.MaxUint16:
+		writeMessagePackHeader(w, 0xc8, uint16(n))
+	default:
+		writeMessagePackHeader(w, 0xc9, uint32(n))
+	}
+	w.WriteByte(byte(typ))
+	w.Write(data)
+}
+
+// writeMessagePackTime writes t as the -1 timestamp extension, in its 32, 64 or 96 bit form.
+func writeMessagePackTime(w *bytes.Buffer, t time.Time) {
+	sec, nsec := t.Unix(), uint32(t.Nanosecond())
+	var data []byte
+	switch {
+	case sec>>34 != 0:
+		data = make([]byte, 12)
+		binary.BigEndian.PutUint32(data, nsec)
+		binary.BigEndian.PutUint64(data[4:], uint64(sec))
+	case nsec == 0 && sec <= math.MaxUint32:
+		data = make([]byte, 4)
+		binary.BigEndian.PutUint32(data, uint32(sec))
+	default:
+		data = make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(nsec)<<34|uint64(sec))
+	}
+	writeMessagePackExtension(w, -1, data)
+}
+
+func readMessagePack(r *bytes.Reader, depth int) (interface{}, error) {
+	format, err := r.ReadByte()
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	switch {
+	case format <= 0x7f:
+		return int64(format), nil
+	case format >= 0xe0:
+		return int64(int8(format)), nil
+	case format&0xf0 == 0x80:
+		return readMessagePackMap(r, int(format&0x0f), depth)
+	case format&0xf0 == 0x90:
+		return readMessagePackArray(r, int(format&0x0f), depth)
+	case format&0xe0 == 0xa0:
+		return readMessagePackString(r, int(format&0x1f))
+	}
+
+	switch format {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := readMessagePackSize(r, format-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackBytes(r, n)
+	case 0xca:
+		var f float32
+		err := binary.Read(r, binary.BigEndian, &f)
+		return float64(f), messagePackReadError(err)
+	case 0xcb:
+		var f float64
+		err := binary.Read(r, binary.BigEndian, &f)
+		return f, messagePackReadError(err)
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return readMessagePackInt(r, format-0xcc, false)
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		return readMessagePackInt(r, format-0xd0, true)
+	case 0xd9, 0xda, 0xdb:
+		n, err := readMessagePackSize(r, format-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackString(r, n)
+	case 0xdc, 0xdd:
+		n, err := readMessagePackSize(r, format-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackArray(r, n, depth)
+	case 0xde, 0xdf:
+		n, err := readMessagePackSize(r, format-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackMap(r, n, depth)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := readMessagePackSize(r, format-0xc7)
+		if err != nil {
+			return nil, err
+		}
+		return readMessagePackExtension(r, n)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return readMessagePackExtension(r, 1<<(format-0xd4))
+	}
+	return nil, fmt.Errorf("rest: unsupported MessagePack format 0x%x", format)
+}
+
+// readMessagePackSize reads a big endian length of 1, 2 or 4 bytes for exp 0, 1 or 2.
+func readMessagePackSize(r *bytes.Reader, exp byte) (int, error) {
+	u, err := readMessagePackInt(r, exp, false)
+	if err != nil {
+		return 0, err
+	}
+	n := u.(uint64)
+	if n > uint64(r.Len()) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return int(n), nil
+}
+
+// readMessagePackInt reads a big endian integer of 1, 2, 4 or 8 bytes for exp 0 to 3.
+func readMessagePackInt(r *bytes.Reader, exp byte, signed bool) (interface{}, error) {
+	b, err := readMessagePackBytes(r, 1<<exp)
+	if err != nil {
+		return nil, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	if !signed {
+		return u, nil
+	}
+	shift := 64 - 8*len(b)
+	return int64(u<<shift) >> shift, nil
+}
+
+func readMessagePackBytes(r *bytes.Reader, n int) ([]byte, error) {
+	if n > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return b, messagePackReadError(err)
+}
+
+func readMessagePackString(r *bytes.Reader, n int) (interface{}, error) {
+	b, err := readMessagePackBytes(r, n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func readMessagePackArray(r *bytes.Reader, n, depth int) (interface{}, error) {
+	if depth >= maxMessagePackDepth {
+		return nil, errMessagePackDepth
+	}
+	if n > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		item, err := readMessagePack(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// messagePackMap keeps the entries of a decoded map in order, whatever the type of their keys.
+type messagePackMap []messagePackEntry
+
+type messagePackEntry struct {
+	key, value interface{}
+}
+
+func readMessagePackMap(r *bytes.Reader, n, depth int) (interface{}, error) {
+	if depth >= maxMessagePackDepth {
+		return nil, errMessagePackDepth
+	}
+	if n > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	m := make(messagePackMap, 0, n)
+	for i := 0; i < n; i++ {
+		key, err := readMessagePack(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readMessagePack(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, messagePackEntry{key: key, value: value})
+	}
+	return m, nil
+}
+
+// readMessagePackExtension reads the type and n bytes of data of an extension, decoding timestamps.
+func readMessagePackExtension(r *bytes.Reader, n int) (interface{}, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data, err := readMessagePackBytes(r, n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ) != -1 {
+		return MessagePackExtension{Type: int8(typ), Data: data}, nil
+	}
+
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		u := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(u&(1<<34-1)), int64(u>>34)).UTC(), nil
+	case 12:
+		return time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data))).UTC(), nil
+	}
+	return nil, fmt.Errorf("rest: invalid MessagePack timestamp of %d bytes", n)
+}
+
+// assignMessagePack stores a decoded value into v, following the rules of encoding/json where they apply.
+func assignMessagePack(v reflect.Value, value interface{}) error {
+	if value == nil {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assignMessagePack(v.Elem(), value)
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(naturalMessagePack(value)))
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch n := value.(type) {
+		case int64:
+			if !v.OverflowInt(n) {
+				v.SetInt(n)
+				return nil
+			}
+		case uint64:
+			if n <= math.MaxInt64 && !v.OverflowInt(int64(n)) {
+				v.SetInt(int64(n))
+				return nil
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch n := value.(type) {
+		case int64:
+			if n >= 0 && !v.OverflowUint(uint64(n)) {
+				v.SetUint(uint64(n))
+				return nil
+			}
+		case uint64:
+			if !v.OverflowUint(n) {
+				v.SetUint(n)
+				return nil
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		switch n := value.(type) {
+		case float64:
+			v.SetFloat(n)
+			return nil
+		case int64:
+			v.SetFloat(float64(n))
+			return nil
+		case uint64:
+			v.SetFloat(float64(n))
+			return nil
+		}
+	case reflect.String:
+		switch s := value.(type) {
+		case string:
+			v.SetString(s)
+			return nil
+		case []byte:
+			v.SetString(string(s))
+			return nil
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			switch b := value.(type) {
+			case []byte:
+				v.SetBytes(b)
+				return nil
+			case string:
+				v.SetBytes([]byte(b))
+				return nil
+			}
+		}
+		if items, ok := value.([]interface{}); ok {
+			s := reflect.MakeSlice(v.Type(), len(items), len(items))
+			for i, item := range items {
+				if err := assignMessagePack(s.Index(i), item); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return nil
+		}
+	case reflect.Array:
+		if b, ok := value.([]byte); ok && v.Type().Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(v, reflect.ValueOf(b))
+			for i := len(b); i < v.Len(); i++ {
+				v.Index(i).SetUint(0)
+			}
+			return nil
+		}
+		if items, ok := value.([]interface{}); ok {
+			for i := 0; i < v.Len(); i++ {
+				if i >= len(items) {
+					v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+					continue
+				}
+				if err := assignMessagePack(v.Index(i), items[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if m, ok := value.(messagePackMap); ok {
+			if v.IsNil() {
+				v.Set(reflect.MakeMapWithSize(v.Type(), len(m)))
+			}
+			for _, entry := range m {
+				key := reflect.New(v.Type().Key()).Elem()
+				if key.Kind() == reflect.String {
+					key.SetString(messagePackKey(entry.key))
+				} else if err := assignMessagePack(key, entry.key); err != nil {
+					return err
+				}
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := assignMessagePack(elem, entry.value); err != nil {
+					return err
+				}
+				v.SetMapIndex(key, elem)
+			}
+			return nil
+		}
+	case reflect.Struct:
+		switch value := value.(type) {
+		case time.Time:
+			if v.Type() == messagePackTimeType {
+				v.Set(reflect.ValueOf(value))
+				return nil
+			}
+		case MessagePackExtension:
+			if v.Type() == messagePackExtensionType {
+				v.Set(reflect.ValueOf(value))
+				return nil
+			}
+		case messagePackMap:
+			fields := messagePackFields(v.Type())
+			for _, entry := range value {
+				name, ok := entry.key.(string)
+				if !ok {
+					continue
+				}
+				field, ok := lookupMessagePackField(fields, name)
+				if !ok {
+					continue
+				}
+				fv, ok := messagePackSettableField(v, field.index)
+				if !ok {
+					continue
+				}
+				if err := assignMessagePack(fv, entry.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("rest: cannot decode MessagePack %s into %s", messagePackKind(value), v.Type())
+}
+
+// naturalMessagePack returns value as stored into an interface{}, maps keyed by the string form of their keys.
+func naturalMessagePack(value interface{}) interface{} {
+	switch value := value.(type) {
+	case messagePackMap:
+		m := make(map[string]interface{}, len(value))
+		for _, entry := range value {
+			m[messagePackKey(entry.key)] = naturalMessagePack(entry.value)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = naturalMessagePack(item)
+		}
+	}
+	return value
+}
+
+// messagePackKey returns the string form of a decoded map key.
+func messagePackKey(key interface{}) string {
+	switch key := key.(type) {
+	case string:
+		return key
+	case []byte:
+		return string(key)
+	}
+	return fmt.Sprint(naturalMessagePack(key))
+}
+
+func messagePackKind(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "bool"
+	case int64, uint64:
+		return "integer"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case []byte:
+		return "bin"
+	case []interface{}:
+		return "array"
+	case messagePackMap:
+		return "map"
+	case time.Time:
+		return "timestamp"
+	}
+	return "extension"
+}
+
+type messagePackField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var messagePackFieldCache sync.Map
+
+// messagePackFields returns the encoded fields of struct type t, inlining untagged embedded structs.
+func messagePackFields(t reflect.Type) []messagePackField {
+	if fields, ok := messagePackFieldCache.Load(t); ok {
+		return fields.([]messagePackField)
+	}
+	fields := appendMessagePackFields(nil, t, nil)
+	messagePackFieldCache.Store(t, fields)
+	return fields
+}
+
+func appendMessagePackFields(fields []messagePackField, t reflect.Type, index []int) []messagePackField {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("msgpack")
+		if !ok {
+			tag = f.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		embedded := f.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+		if f.Anonymous && len(name) == 0 && embedded.Kind() == reflect.Struct && embedded != t && embedded != messagePackTimeType {
+			fields = appendMessagePackFields(fields, embedded, fieldIndex)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		fields = append(fields, messagePackField{name: name, index: fieldIndex, omitEmpty: strings.Contains(","+options+",", ",omitempty,")})
+	}
+	return fields
+}
+
+// lookupMessagePackField finds the field named name, preferring an exact match to a case-insensitive one.
+func lookupMessagePackField(fields []messagePackField, name string) (messagePackField, bool) {
+	for _, field := range fields {
+		if field.name == name {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, name) {
+			return field, true
+		}
+	}
+	return messagePackField{}, false
+}
+
+// messagePackFieldValue returns the field of v at index, unless it's promoted through a nil pointer.
+func messagePackFieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// messagePackSettableField returns the field of v at index, allocating the embedded pointers on the way.
+func messagePackSettableField(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, v.CanSet()
+}
+
+func isEmptyMessagePackValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+var errMessagePackDepth = errors.New("rest: MessagePack nested too deeply")
+
+func messagePackReadError(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,174 @@
+package rest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type msgpackEvent struct {
+	Name    string            `json:"name"`
+	Count   int64             `json:"count"`
+	Offset  int               `json:"offset"`
+	Ratio   float64           `json:"ratio"`
+	Big     uint64            `json:"big"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Payload []byte            `json:"payload"`
+	Parent  *msgpackEvent     `json:"parent"`
+}
+
+func TestShouldEncodeMessagePack(t *testing.T) {
+	b, err := ioutil.ReadAll(mustEncodeMessagePack(t, map[string]interface{}{"compact": true, "schema": 0}))
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	expected := []byte("\x82\xa7compact\xc3\xa6schema\x00")
+	if !bytes.Equal(b, expected) {
+		t.Errorf("Expected: [% x] got: [% x]", expected, b)
+	}
+}
+
+func TestShouldRoundTripMessagePack(t *testing.T) {
+	event := msgpackEvent{
+		Name:    strings.Repeat("n", 300),
+		Count:   -70000,
+		Offset:  -5,
+		Ratio:   0.25,
+		Big:     math.MaxUint64,
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"env": "prod"},
+		Payload: []byte{0, 1, 2},
+		Parent:  &msgpackEvent{Name: "root", Count: 200},
+	}
+
+	b, _ := ioutil.ReadAll(mustEncodeMessagePack(t, event))
+	var decoded msgpackEvent
+	if err := DecodeMessagePack(b, &decoded); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, event) {
+		t.Errorf("Expected: [%+v] got: [%+v]", event, decoded)
+	}
+}
+
+func TestShouldDecodeMessagePackFormats(t *testing.T) {
+	// bin 8, float 32, int 16, array 16 and a non-string map key
+	b := []byte("\x84\xa3bin\xc4\x02hi\xa5float\xca\x3f\xc0\x00\x00\xa3int\xd1\xff\x00\x01\xdc\x00\x01\xc0")
+	var v map[string]interface{}
+	if err := DecodeMessagePack(b, &v); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	expected := map[string]interface{}{"bin": []byte("hi"), "float": 1.5, "int": int64(-256), "1": []interface{}{nil}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected: [%v] got: [%v]", expected, v)
+	}
+
+	if err := DecodeMessagePack([]byte("\x92\x01"), &v); err == nil {
+		t.Error("Expected an error for truncated MessagePack")
+	}
+}
+
+func TestShouldRejectDeeplyNestedMessagePack(t *testing.T) {
+	var v interface{}
+	if err := DecodeMessagePack(bytes.Repeat([]byte{0x91}, 5<<20), &v); err != errMessagePackDepth {
+		t.Errorf("Expected error: [%v] got: [%v]", errMessagePackDepth, err)
+	}
+
+	nested := append(bytes.Repeat([]byte{0x91}, 100), 0xc0)
+	if err := DecodeMessagePack(nested, &v); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestShouldDecodeMessagePackExtensions(t *testing.T) {
+	// a timestamp 32, extension type -1, and a fixext 2 of type 5
+	var v []interface{}
+	if err := DecodeMessagePack([]byte("\x92\xd6\xff\x00\x00\x00\x01\xd5\x05\xab\xcd"), &v); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if ts, ok := v[0].(time.Time); !ok || !ts.Equal(time.Unix(1, 0)) {
+		t.Errorf("Expected timestamp: [%v] got: [%v]", time.Unix(1, 0), v[0])
+	}
+	expected := MessagePackExtension{Type: 5, Data: []byte{0xab, 0xcd}}
+	if !reflect.DeepEqual(v[1], expected) {
+		t.Errorf("Expected: [%v] got: [%v]", expected, v[1])
+	}
+}
+
+func TestShouldEncodeMessagePackNatively(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected []byte
+	}{
+		{[]byte("hi"), []byte("\xc4\x02hi")},
+		{math.NaN(), []byte("\xcb\x7f\xf8\x00\x00\x00\x00\x00\x01")},
+		{math.Inf(1), []byte("\xcb\x7f\xf0\x00\x00\x00\x00\x00\x00")},
+		{float32(1.5), []byte("\xca\x3f\xc0\x00\x00")},
+		{time.Unix(1, 0), []byte("\xd6\xff\x00\x00\x00\x01")},
+		{MessagePackExtension{Type: 5, Data: []byte{0xab, 0xcd}}, []byte("\xd5\x05\xab\xcd")},
+		{struct {
+			Name    string `msgpack:"n"`
+			Skipped int    `json:"-"`
+			Empty   string `json:"e,omitempty"`
+		}{Name: "x", Skipped: 1}, []byte("\x81\xa1n\xa1x")},
+	}
+	for _, test := range tests {
+		b, _ := ioutil.ReadAll(mustEncodeMessagePack(t, test.value))
+		if !bytes.Equal(b, test.expected) {
+			t.Errorf("Expected: [% x] got: [% x]", test.expected, b)
+		}
+	}
+}
+
+func TestShouldRoundTripMessagePackTimestamps(t *testing.T) {
+	for _, ts := range []time.Time{time.Unix(1, 0), time.Unix(1700000000, 123456789), time.Unix(-1, 5), time.Unix(1<<35, 0)} {
+		b, _ := ioutil.ReadAll(mustEncodeMessagePack(t, ts))
+		var decoded time.Time
+		if err := DecodeMessagePack(b, &decoded); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		if !decoded.Equal(ts) {
+			t.Errorf("Expected: [%v] got: [%v]", ts, decoded)
+		}
+	}
+}
+
+func TestShouldExchangeMessagePack(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Write(b)
+	}))
+	defer ts.Close()
+
+	re, err := New().Post(ts.URL, mustEncodeMessagePack(t, msgpackEvent{Name: "created"}), MessagePackRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, re.Header, "Content-Type", "application/msgpack")
+
+	codec, ok := CodecFor(re.Header.Get("Content-Type"))
+	if !ok {
+		t.Fatal("Expected a MessagePack codec")
+	}
+	var event msgpackEvent
+	if err := codec.Unmarshal(re.Body, &event); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, event.Name, "created")
+}
+
+func mustEncodeMessagePack(t *testing.T, v interface{}) *bytes.Reader {
+	r, err := EncodeMessagePack(v)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return r.(*bytes.Reader)
+}
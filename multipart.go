@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// Parts splits a multipart (e.g. multipart/mixed) body into one ResponseEntity per part, holding the part
+// headers and body. Parts of type application/http are parsed as the embedded HTTP responses they carry.
+func (re *ResponseEntity) Parts() ([]ResponseEntity, error) {
+	mediaType, params, err := mime.ParseMediaType(re.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") || len(params["boundary"]) == 0 {
+		return nil, fmt.Errorf("rest: %q isn't a multipart content type", mediaType)
+	}
+
+	var parts []ResponseEntity
+	mr := multipart.NewReader(bytes.NewReader(re.Body), params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		part, err := readPart(p, re.StatusCode)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+}
+
+func readPart(p *multipart.Part, statusCode int) (ResponseEntity, error) {
+	header := http.Header(p.Header)
+	body, err := ioutil.ReadAll(p)
+	if err != nil {
+		return ResponseEntity{}, err
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType != "application/http" {
+		return ResponseEntity{StatusCode: statusCode, Header: header, Body: body}, nil
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(body)), nil)
+	if err != nil {
+		return ResponseEntity{}, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return ResponseEntity{}, err
+	}
+	return ResponseEntity{StatusCode: res.StatusCode, Header: res.Header, Body: resBody}, nil
+}
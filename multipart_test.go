@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestShouldParseMultipartMixedParts(t *testing.T) {
+	body := "--batch_42\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-ID: <item1>\r\n" +
+		"\r\n" +
+		"{\"id\":1}\r\n" +
+		"--batch_42\r\n" +
+		"Content-Type: application/http\r\n" +
+		"\r\n" +
+		"HTTP/1.1 404 Not Found\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 21\r\n" +
+		"\r\n" +
+		"{\"error\":\"not found\"}\r\n" +
+		"--batch_42--\r\n"
+	re := ResponseEntity{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"multipart/mixed; boundary=batch_42"}},
+		Body:       []byte(body),
+	}
+
+	parts, err := re.Parts()
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("Expected parts: [%v] got: [%v]", 2, len(parts))
+	}
+
+	assertStatusCode(t, parts[0].StatusCode, http.StatusOK)
+	assertHeader(t, parts[0].Header, "Content-ID", "<item1>")
+	assertBody(t, parts[0].BodyString(), "{\"id\":1}")
+
+	assertStatusCode(t, parts[1].StatusCode, http.StatusNotFound)
+	assertHeader(t, parts[1].Header, "Content-Type", "application/json")
+	assertBody(t, parts[1].BodyString(), "{\"error\":\"not found\"}")
+}
+
+func TestShouldNotParsePartsOfNonMultipart(t *testing.T) {
+	re := ResponseEntity{Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte("{}")}
+	if _, err := re.Parts(); err == nil {
+		t.Error("Expected error for a non multipart response")
+	}
+}
@@ -0,0 +1,183 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"reflect"
+)
+
+// StreamError is the error object a streaming endpoint sends in place of its data, e.g. {"error": "quota exceeded"}.
+type StreamError struct {
+	Err json.RawMessage
+}
+
+func (e *StreamError) Error() string {
+	var message string
+	if err := json.Unmarshal(e.Err, &message); err == nil {
+		return "rest: stream error: " + message
+	}
+	return "rest: stream error: " + string(e.Err)
+}
+
+// streamError returns the StreamError when raw is an object with a non null "error" field.
+func streamError(raw []byte) (*StreamError, bool) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, false
+	}
+	value, ok := object["error"]
+	if !ok || string(value) == "null" {
+		return nil, false
+	}
+	return &StreamError{Err: value}, true
+}
+
+// DecodeNDJSON decodes the newline delimited JSON encoded b into the slice pointed to by items.
+// When summary isn't nil the last line is decoded into summary instead of being appended to items.
+// A first line that is an object with an "error" field is returned as a *StreamError.
+func DecodeNDJSON(b []byte, items interface{}, summary interface{}) error {
+	slice := reflect.ValueOf(items)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return errors.New("rest: DecodeNDJSON items must be a pointer to a slice")
+	}
+	slice = slice.Elem()
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 64*1024), len(b)+1)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(lines) > 0 {
+		if err, ok := streamError(lines[0]); ok {
+			return err
+		}
+	}
+
+	if summary != nil && len(lines) > 0 {
+		if err := json.Unmarshal(lines[len(lines)-1], summary); err != nil {
+			return err
+		}
+		lines = lines[:len(lines)-1]
+	}
+
+	for _, line := range lines {
+		item := reflect.New(slice.Type().Elem())
+		if err := json.Unmarshal(line, item.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+	return nil
+}
+
+// DecodeJSONStream calls fn for every item read from r, either the elements of a JSON array or a sequence of
+// JSON values such as NDJSON, without buffering the whole stream. When the first value is an object with an
+// "error" field it's returned as a *StreamError instead, for endpoints that fail before streaming data.
+func DecodeJSONStream(r io.Reader, fn func(item json.RawMessage) error) error {
+	br := bufio.NewReader(r)
+	first, err := peekJSON(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(br)
+	if first == '[' {
+		if _, err := d.Token(); err != nil {
+			return err
+		}
+		for d.More() {
+			var item json.RawMessage
+			if err := d.Decode(&item); err != nil {
+				return err
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		_, err := d.Token()
+		return err
+	}
+
+	for i := 0; ; i++ {
+		var item json.RawMessage
+		if err := d.Decode(&item); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if i == 0 {
+			if err, ok := streamError(item); ok {
+				return err
+			}
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// errStopStream stops DecodeJSONStream once the consumer of GetJSONStream breaks out of its loop.
+var errStopStream = errors.New("rest: stream stopped")
+
+// GetJSONStream gets an NDJSON or JSON array stream from the given URL, yielding its items as they're
+// decoded: for item, err := range c.GetJSONStream(url, nil). The request is sent when iteration starts,
+// and a request, status or decoding error is yielded last.
+func (c *Client) GetJSONStream(url string, requestCallback func(r *http.Request)) iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		se, err := c.GetStream(url, func(r *http.Request) {
+			r.Header.Set("Accept", "application/x-ndjson, application/json")
+			if requestCallback != nil {
+				requestCallback(r)
+			}
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer se.Body.Close()
+
+		if se.StatusCode < http.StatusOK || se.StatusCode >= http.StatusMultipleChoices {
+			yield(nil, fmt.Errorf("rest: JSON stream from %s failed with status %d", url, se.StatusCode))
+			return
+		}
+
+		err = DecodeJSONStream(se.Body, func(item json.RawMessage) error {
+			if !yield(item, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil && err != errStopStream {
+			yield(nil, err)
+		}
+	}
+}
+
+// peekJSON returns the first non whitespace byte of r without consuming it.
+func peekJSON(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, r.UnreadByte()
+		}
+	}
+}
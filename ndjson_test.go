@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShouldDecodeNDJSONWithSummary(t *testing.T) {
+	b := []byte("{\"id\":1}\n{\"id\":2}\n\n{\"id\":3}\n{\"total\":3,\"complete\":true}\n")
+
+	var items []struct{ ID int }
+	summary := struct {
+		Total    int
+		Complete bool
+	}{}
+
+	if err := DecodeNDJSON(b, &items, &summary); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(items) != 3 || items[0].ID != 1 || items[2].ID != 3 {
+		t.Errorf("Expected 3 items got: [%v]", items)
+	}
+
+	if summary.Total != 3 || !summary.Complete {
+		t.Errorf("Expected summary got: [%v]", summary)
+	}
+}
+
+func TestShouldDecodeNDJSONWithoutSummary(t *testing.T) {
+	var items []map[string]int
+	if err := DecodeNDJSON([]byte("{\"id\":1}\n{\"id\":2}"), &items, nil); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(items) != 2 || items[1]["id"] != 2 {
+		t.Errorf("Expected 2 items got: [%v]", items)
+	}
+
+	if err := DecodeNDJSON([]byte("{}"), items, nil); err == nil {
+		t.Error("Expected error for non pointer items")
+	}
+}
+
+func TestShouldReturnNDJSONStreamError(t *testing.T) {
+	var items []map[string]int
+	err := DecodeNDJSON([]byte("{\"error\":\"quota exceeded\"}\n"), &items, nil)
+
+	streamErr, ok := err.(*StreamError)
+	if !ok || streamErr.Error() != "rest: stream error: quota exceeded" {
+		t.Errorf("Expected stream error got: [%v]", err)
+	}
+}
+
+func TestShouldDecodeJSONStreamArray(t *testing.T) {
+	var ids []string
+	err := DecodeJSONStream(strings.NewReader(" [{\"id\":1}, {\"id\":2}, {\"id\":3}]"), func(item json.RawMessage) error {
+		ids = append(ids, string(item))
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if strings.Join(ids, ",") != "{\"id\":1},{\"id\":2},{\"id\":3}" {
+		t.Errorf("Expected 3 items got: [%v]", ids)
+	}
+}
+
+func TestShouldDecodeJSONStreamValues(t *testing.T) {
+	var ids []string
+	err := DecodeJSONStream(strings.NewReader("{\"id\":1}\n{\"id\":2}\n"), func(item json.RawMessage) error {
+		ids = append(ids, string(item))
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(ids) != 2 {
+		t.Errorf("Expected 2 items got: [%v]", ids)
+	}
+}
+
+func TestShouldReturnJSONStreamErrorFirst(t *testing.T) {
+	called := false
+	err := DecodeJSONStream(strings.NewReader("{\"error\":{\"code\":429}}\n{\"id\":1}\n"), func(item json.RawMessage) error {
+		called = true
+		return nil
+	})
+
+	streamErr, ok := err.(*StreamError)
+	if !ok || string(streamErr.Err) != "{\"code\":429}" {
+		t.Errorf("Expected stream error got: [%v]", err)
+	}
+	if called {
+		t.Error("Expected no items after the stream error")
+	}
+}
+
+func TestShouldGetJSONStreamLazily(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Accept"))
+		w.Write([]byte("{\"id\":1}\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		w.Write([]byte("{\"id\":2}\n{\"id\":3}\n"))
+	}))
+	defer ts.Close()
+
+	var ids []int
+	for item, err := range New().GetJSONStream(ts.URL, nil) {
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		var v struct{ ID int }
+		json.Unmarshal(item, &v)
+		ids = append(ids, v.ID)
+		if len(ids) == 1 {
+			// the first item arrives while the server still holds back the rest
+			close(release)
+		}
+		if len(ids) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("Expected ids: [%v] got: [%v]", []int{1, 2}, ids)
+	}
+}
+
+func TestShouldYieldJSONStreamStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var errs []error
+	for _, err := range New().GetJSONStream(ts.URL, nil) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || errs[0] == nil {
+		t.Errorf("Expected a single error got: [%v]", errs)
+	}
+}
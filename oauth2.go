@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how long before their expiry client credentials tokens are refreshed.
+const tokenExpiryDelta = 10 * time.Second
+
+// ClientCredentials configures the OAuth2 client credentials grant.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Client sends the token requests, New() when nil.
+	Client *Client
+}
+
+type clientCredentialsTokenProvider struct {
+	config ClientCredentials
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewClientCredentialsTokenProvider returns a TokenProvider obtaining access tokens with the OAuth2 client
+// credentials grant, caching each until shortly before it expires.
+func NewClientCredentialsTokenProvider(config ClientCredentials) TokenProvider {
+	if config.Client == nil {
+		config.Client = New()
+	}
+	return &clientCredentialsTokenProvider{config: config}
+}
+
+// WithClientCredentials sends an access token obtained with the OAuth2 client credentials grant with every
+// request, see NewClientCredentialsTokenProvider.
+func WithClientCredentials(config ClientCredentials) Option {
+	return WithTokenProvider(NewClientCredentialsTokenProvider(config))
+}
+
+// Token implements TokenProvider.
+func (p *clientCredentialsTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.token) > 0 && (p.expiry.IsZero() || time.Now().Before(p.expiry.Add(-tokenExpiryDelta))) {
+		return p.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(p.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(p.config.Scopes, " "))
+	}
+	start := time.Now()
+	re, err := p.config.Client.withContext(ctx).PostForm(p.config.TokenURL, form, func(r *http.Request) {
+		r.Header.Set("Accept", "application/json")
+		r.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := DecodeJSON(re.Body, &token); err != nil && re.StatusCode < http.StatusBadRequest {
+		return "", err
+	}
+	if re.StatusCode >= http.StatusBadRequest || len(token.Error) > 0 {
+		return "", fmt.Errorf("rest: token request failed with status %d: %s %s", re.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if len(token.AccessToken) == 0 {
+		return "", errors.New("rest: token response has no access_token")
+	}
+
+	p.token = token.AccessToken
+	p.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		p.expiry = start.Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return p.token, nil
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShouldAuthenticateWithClientCredentials(t *testing.T) {
+	tokenServer, issued := tokenTestServer("3600")
+	defer tokenServer.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer ts.Close()
+
+	c := New(WithClientCredentials(ClientCredentials{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+	}))
+	for i := 0; i < 2; i++ {
+		re, err := c.Get(ts.URL, JSONRequestCallback)
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		assertBody(t, re.BodyString(), "Bearer token-1")
+	}
+
+	if n := atomic.LoadInt32(issued); n != 1 {
+		t.Errorf("Expected tokens issued: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldRefreshExpiringClientCredentialsToken(t *testing.T) {
+	tokenServer, issued := tokenTestServer("5")
+	defer tokenServer.Close()
+
+	p := NewClientCredentialsTokenProvider(ClientCredentials{TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "secret"})
+	p.Token(context.Background())
+	token, err := p.Token(context.Background())
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if token != "token-2" || atomic.LoadInt32(issued) != 2 {
+		t.Errorf("Expected a refreshed token got: [%v]", token)
+	}
+}
+
+func TestShouldFailOnRejectedClientCredentials(t *testing.T) {
+	tokenServer, _ := tokenTestServer("3600")
+	defer tokenServer.Close()
+
+	p := NewClientCredentialsTokenProvider(ClientCredentials{TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "wrong"})
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Error("Expected error for rejected credentials")
+	}
+}
+
+// tokenTestServer issues numbered tokens expiring in expiresIn seconds to client:secret.
+func tokenTestServer(expiresIn string) (*httptest.Server, *int32) {
+	issued := new(int32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != "client" || secret != "secret" || r.PostFormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("{\"error\":\"invalid_client\"}"))
+			return
+		}
+		n := atomic.AddInt32(issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{\"access_token\":\"token-" + strconv.Itoa(int(n)) + "\",\"token_type\":\"Bearer\",\"expires_in\":" + expiresIn + "}"))
+	}))
+	return ts, issued
+}
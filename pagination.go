@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrPaginationLimit is returned along with the pages fetched so far when pagination stops at the
+// configured time budget or page count.
+var ErrPaginationLimit = errors.New("rest: pagination limit reached")
+
+// DefaultMaxPages caps pagination when no WithMaxPages is given.
+const DefaultMaxPages = 1000
+
+// WithPaginationDeadline bounds the total time spent following pages.
+func WithPaginationDeadline(d time.Duration) Option {
+	return func(c *Client) {
+		c.paginationDeadline = d
+	}
+}
+
+// WithMaxPages bounds the number of pages followed, DefaultMaxPages by default.
+func WithMaxPages(n int) Option {
+	return func(c *Client) {
+		c.maxPages = n
+	}
+}
+
+// GetAllPages gets the given URL and every page after it, following rel="next" Link headers until there's
+// no next page. A page answered with a non 2xx status fails pagination, returning the pages before it.
+// Pagination also stops, returning the pages so far and ErrPaginationLimit, once the pagination deadline
+// or maximum page count is reached.
+func (c *Client) GetAllPages(url string, requestCallback func(r *http.Request)) ([]ResponseEntity, error) {
+	var pages []ResponseEntity
+	for re, err := range c.Paginate(url, requestCallback) {
+		if err != nil {
+			return pages, err
+		}
+		pages = append(pages, re)
+	}
+	return pages, nil
+}
+
+// Paginate is GetAllPages fetching each page lazily as the loop asks for it:
+// for page, err := range c.Paginate(url, nil). An error ends the pages, and the pagination deadline
+// starts with the loop.
+func (c *Client) Paginate(url string, requestCallback func(r *http.Request)) iter.Seq2[ResponseEntity, error] {
+	return func(yield func(ResponseEntity, error) bool) {
+		fail := func(err error) {
+			yield(ResponseEntity{Header: make(http.Header)}, err)
+		}
+		maxPages := c.maxPages
+		if maxPages <= 0 {
+			maxPages = DefaultMaxPages
+		}
+		url, err := c.resolveBaseURL(url)
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		cc, outOfTime, cancel := c.paginationContext()
+		defer cancel()
+
+		for pages := 0; len(url) > 0; pages++ {
+			if pages >= maxPages {
+				fail(fmt.Errorf("%w: %d pages", ErrPaginationLimit, maxPages))
+				return
+			}
+			if outOfTime() {
+				fail(c.paginationDeadlineError())
+				return
+			}
+
+			re, err := cc.Get(url, requestCallback)
+			if err != nil && outOfTime() {
+				fail(c.paginationDeadlineError())
+				return
+			}
+			if err != nil {
+				fail(err)
+				return
+			}
+			if re.StatusCode < http.StatusOK || re.StatusCode >= http.StatusMultipleChoices {
+				yield(re, fmt.Errorf("rest: page %s failed with status %d", url, re.StatusCode))
+				return
+			}
+			if !yield(re, nil) {
+				return
+			}
+
+			next, ok := linkRelations(re.Header)["next"]
+			if !ok {
+				return
+			}
+			if url, err = resolveReference(url, next); err != nil {
+				fail(err)
+				return
+			}
+		}
+	}
+}
+
+// paginationContext returns the client bound to the pagination deadline, along with a function reporting
+// whether the deadline passed, rather than the Client's own context being done, and one releasing it.
+func (c *Client) paginationContext() (*Client, func() bool, context.CancelFunc) {
+	ctx, cancel := c.context(), context.CancelFunc(func() {})
+	if c.paginationDeadline > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.paginationDeadline)
+	}
+	outOfTime := func() bool {
+		return ctx.Err() != nil && c.context().Err() == nil
+	}
+	return c.withContext(ctx), outOfTime, cancel
+}
+
+func (c *Client) paginationDeadlineError() error {
+	return fmt.Errorf("%w: %v deadline", ErrPaginationLimit, c.paginationDeadline)
+}
+
+// linkRelations returns the target of every relation in the RFC 8288 Link headers.
+func linkRelations(header http.Header) map[string]string {
+	relations := make(map[string]string)
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			segments := strings.Split(link, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			target = target[1 : len(target)-1]
+
+			for _, param := range segments[1:] {
+				parts := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(parts) != 2 || !strings.EqualFold(parts[0], "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(parts[1], "\"")) {
+					rel = strings.ToLower(rel)
+					if _, ok := relations[rel]; !ok {
+						relations[rel] = target
+					}
+				}
+			}
+		}
+	}
+	return relations
+}
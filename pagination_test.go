@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestShouldGetAllPages(t *testing.T) {
+	ts := pagesTestServer(3, 0)
+	defer ts.Close()
+
+	pages, err := New().GetAllPages(ts.URL+"/items?page=1", JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(pages) != 3 {
+		t.Fatalf("Expected pages: [%v] got: [%v]", 3, len(pages))
+	}
+	assertBody(t, pages[2].BodyString(), "[3]")
+}
+
+func TestShouldFailPaginationOnErrorPage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Link", "</items?page=2>; rel=\"next\"")
+		w.Write([]byte("[1]"))
+	}))
+	defer ts.Close()
+
+	pages, err := New().GetAllPages(ts.URL+"/items?page=1", JSONRequestCallback)
+	if err == nil {
+		t.Error("Expected the error page to fail pagination")
+	}
+	if len(pages) != 1 {
+		t.Errorf("Expected pages: [%v] got: [%v]", 1, len(pages))
+	}
+}
+
+func TestShouldPaginateLazily(t *testing.T) {
+	ts := pagesTestServer(5, 0)
+	defer ts.Close()
+
+	var requests int
+	c := New(WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			requests++
+			return next(req)
+		}
+	}))
+
+	var bodies []string
+	for page, err := range c.Paginate(ts.URL+"/items?page=1", JSONRequestCallback) {
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if bodies = append(bodies, page.BodyString()); len(bodies) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(bodies, []string{"[1]", "[2]"}) {
+		t.Errorf("Expected pages: [%v] got: [%v]", []string{"[1]", "[2]"}, bodies)
+	}
+	if requests != 2 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 2, requests)
+	}
+}
+
+func TestShouldStopPaginationAtDeadline(t *testing.T) {
+	ts := pagesTestServer(1000, 50*time.Millisecond)
+	defer ts.Close()
+
+	c := New(WithPaginationDeadline(250 * time.Millisecond))
+	pages, err := c.GetAllPages(ts.URL+"/items?page=1", JSONRequestCallback)
+	if !errors.Is(err, ErrPaginationLimit) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrPaginationLimit, err)
+	}
+
+	if len(pages) == 0 || len(pages) > 5 {
+		t.Errorf("Expected some pages before the deadline got: [%v]", len(pages))
+	}
+}
+
+func TestShouldStopPaginationAtMaxPages(t *testing.T) {
+	ts := pagesTestServer(1000, 0)
+	defer ts.Close()
+
+	pages, err := New(WithMaxPages(4)).GetAllPages(ts.URL+"/items?page=1", JSONRequestCallback)
+	if !errors.Is(err, ErrPaginationLimit) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrPaginationLimit, err)
+	}
+
+	if len(pages) != 4 {
+		t.Errorf("Expected pages: [%v] got: [%v]", 4, len(pages))
+	}
+}
+
+func TestShouldParseLinkRelations(t *testing.T) {
+	header := http.Header{"Link": {
+		"<https://api.example.com/items?page=3>; rel=\"next\", <https://api.example.com/items?page=1>; rel=\"prev first\"",
+		"<https://api.example.com/items?page=9>; rel=last",
+	}}
+
+	relations := linkRelations(header)
+	expected := map[string]string{
+		"next":  "https://api.example.com/items?page=3",
+		"prev":  "https://api.example.com/items?page=1",
+		"first": "https://api.example.com/items?page=1",
+		"last":  "https://api.example.com/items?page=9",
+	}
+	if fmt.Sprint(relations) != fmt.Sprint(expected) {
+		t.Errorf("Expected relations: [%v] got: [%v]", expected, relations)
+	}
+}
+
+// pagesTestServer serves the given number of pages linked by relative rel="next" links.
+func pagesTestServer(pages int, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < pages {
+			w.Header().Set("Link", fmt.Sprintf("</items?page=%d>; rel=\"next\"", page+1))
+		}
+		fmt.Fprintf(w, "[%d]", page)
+	}))
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PathParams holds the values substituted for the {name} variables of a path template.
+type PathParams map[string]string
+
+// ExpandPath substitutes each {name} variable of template with its path escaped value from params,
+// e.g. "/users/{id}/orders/{orderId}". A variable missing from params is an error.
+func ExpandPath(template string, params PathParams) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("rest: unclosed path variable in %q", template)
+		}
+		end += start
+
+		name := template[start+1 : end]
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("rest: missing path variable %q", name)
+		}
+		b.WriteString(template[:start])
+		b.WriteString(url.PathEscape(value))
+		template = template[end+1:]
+	}
+}
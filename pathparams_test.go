@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestShouldExpandPath(t *testing.T) {
+	path, err := ExpandPath("/users/{id}/orders/{orderId}", PathParams{"id": "42", "orderId": "7/8 ?x"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if path != "/users/42/orders/7%2F8%20%3Fx" {
+		t.Errorf("Expected path: [%v] got: [%v]", "/users/42/orders/7%2F8%20%3Fx", path)
+	}
+}
+
+func TestShouldFailExpandPathWithMissingVariable(t *testing.T) {
+	if _, err := ExpandPath("/users/{id}", PathParams{"userId": "42"}); err == nil {
+		t.Error("Expected error for a missing path variable")
+	}
+
+	if _, err := ExpandPath("/users/{id", PathParams{"id": "42"}); err == nil {
+		t.Error("Expected error for an unclosed path variable")
+	}
+}
+
+func ExampleExpandPath() {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	path, err := ExpandPath("/users/{id}", PathParams{"id": "jose"})
+	if err != nil {
+		return
+	}
+
+	re, err := New(WithBaseURL(ts.URL)).Post(path, EncodeJSON(PathParams{"id": "jose"}), JSONRequestCallback)
+	if err != nil {
+		return
+	}
+
+	fmt.Print(re.BodyString())
+	// Output: {"id":"jose"}
+}
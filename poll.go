@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// PollOption configures a PollUntil.
+type PollOption func(p *poll)
+
+type poll struct {
+	factor          float64
+	maxInterval     time.Duration
+	requestCallback func(r *http.Request)
+}
+
+// PollBackoff multiplies the interval by factor after every poll, up to max when it's positive.
+func PollBackoff(factor float64, max time.Duration) PollOption {
+	return func(p *poll) {
+		p.factor = factor
+		p.maxInterval = max
+	}
+}
+
+// PollRequestCallback calls requestCallback on every poll request.
+func PollRequestCallback(requestCallback func(r *http.Request)) PollOption {
+	return func(p *poll) {
+		p.requestCallback = requestCallback
+	}
+}
+
+// PollUntil gets the given URL every interval until predicate reports the operation done, e.g. once a
+// job's status resource says it finished, and returns the last response. A Retry-After header overrides
+// the interval. Polling stops with the error of a request, of predicate or of ctx once done.
+func (c *Client) PollUntil(ctx context.Context, url string, predicate func(re ResponseEntity) (bool, error), interval time.Duration, opts ...PollOption) (ResponseEntity, error) {
+	p := &poll{}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	c = c.withContext(ctx)
+	for {
+		re, err := c.Get(url, p.requestCallback)
+		if err != nil {
+			return re, err
+		}
+		if done, err := predicate(re); done || err != nil {
+			return re, err
+		}
+
+		select {
+		case <-time.After(retryAfter(re.Header, interval)):
+		case <-ctx.Done():
+			return re, wrapRequestError(http.MethodGet, url, ctx.Err())
+		}
+		interval = p.next(interval)
+	}
+}
+
+// next returns the interval to wait after interval.
+func (p *poll) next(interval time.Duration) time.Duration {
+	if p.factor <= 0 {
+		return interval
+	}
+	interval = time.Duration(float64(interval) * p.factor)
+	if p.maxInterval > 0 && interval > p.maxInterval {
+		return p.maxInterval
+	}
+	return interval
+}
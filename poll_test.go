@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jobStatusTestServer reports a job running for pending polls before it's done.
+func jobStatusTestServer(pending int32) (*httptest.Server, *int32) {
+	var polls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Poll-Header", r.Header.Get("X-Poll"))
+		if atomic.AddInt32(&polls, 1) <= pending {
+			w.Write([]byte("{\"status\":\"running\"}"))
+			return
+		}
+		w.Write([]byte("{\"status\":\"done\"}"))
+	}))
+	return ts, &polls
+}
+
+func jobDone(re ResponseEntity) (bool, error) {
+	var job struct{ Status string }
+	if err := DecodeJSON(re.Body, &job); err != nil {
+		return false, err
+	}
+	return job.Status == "done", nil
+}
+
+func TestShouldPollUntilDone(t *testing.T) {
+	ts, polls := jobStatusTestServer(3)
+	defer ts.Close()
+
+	re, err := New().PollUntil(context.Background(), ts.URL, jobDone, 5*time.Millisecond,
+		PollBackoff(2, 10*time.Millisecond), PollRequestCallback(func(r *http.Request) {
+			r.Header.Set("X-Poll", "yes")
+		}))
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "{\"status\":\"done\"}")
+	assertHeader(t, re.Header, "X-Poll-Header", "yes")
+	if n := atomic.LoadInt32(polls); n != 4 {
+		t.Errorf("Expected polls: [%v] got: [%v]", 4, n)
+	}
+}
+
+func TestShouldStopPollingOnPredicateError(t *testing.T) {
+	ts, polls := jobStatusTestServer(3)
+	defer ts.Close()
+
+	errFailed := errors.New("job failed")
+	_, err := New().PollUntil(context.Background(), ts.URL, func(re ResponseEntity) (bool, error) {
+		return false, errFailed
+	}, time.Millisecond)
+	if err != errFailed {
+		t.Errorf("Expected error: [%v] got: [%v]", errFailed, err)
+	}
+	if n := atomic.LoadInt32(polls); n != 1 {
+		t.Errorf("Expected polls: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldStopPollingWithContext(t *testing.T) {
+	ts, _ := jobStatusTestServer(1000)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := New().PollUntil(ctx, ts.URL, jobDone, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error: [%v] got: [%v]", context.DeadlineExceeded, err)
+	}
+}
+
+func TestShouldBackOffPollInterval(t *testing.T) {
+	p := &poll{factor: 2, maxInterval: 300 * time.Millisecond}
+	interval := 100 * time.Millisecond
+	for _, expected := range []time.Duration{200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		if interval = p.next(interval); interval != expected {
+			t.Errorf("Expected interval: [%v] got: [%v]", expected, interval)
+		}
+	}
+	if interval := (&poll{}).next(time.Second); interval != time.Second {
+		t.Errorf("Expected interval: [%v] got: [%v]", time.Second, interval)
+	}
+}
@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"encoding/json"
+	"mime"
+)
+
+// ProblemDetails struct represents an RFC 7807 application/problem+json body. Members other than the
+// standard ones are kept in Extensions.
+type ProblemDetails struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]json.RawMessage
+}
+
+func (p *ProblemDetails) Error() string {
+	message := "rest: problem " + p.Type
+	if len(p.Title) > 0 {
+		message += ": " + p.Title
+	}
+	if len(p.Detail) > 0 {
+		message += ": " + p.Detail
+	}
+	return message
+}
+
+// UnmarshalJSON decodes a problem+json body, defaulting Type to about:blank as RFC 7807 does.
+func (p *ProblemDetails) UnmarshalJSON(b []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+
+	standard := map[string]interface{}{
+		"type":     &p.Type,
+		"title":    &p.Title,
+		"status":   &p.Status,
+		"detail":   &p.Detail,
+		"instance": &p.Instance,
+	}
+	for name, raw := range members {
+		if v, ok := standard[name]; ok {
+			// a member of the wrong type is ignored, as RFC 7807 asks
+			json.Unmarshal(raw, v)
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]json.RawMessage)
+		}
+		p.Extensions[name] = raw
+	}
+	if len(p.Type) == 0 {
+		p.Type = "about:blank"
+	}
+	return nil
+}
+
+// WithProblemDetails makes responses with an application/problem+json body fail with their
+// *ProblemDetails as the error, so callers can branch on the problem type with errors.As.
+func WithProblemDetails() Option {
+	return func(c *Client) {
+		c.problemErrors = true
+	}
+}
+
+// Problem returns the problem details of an application/problem+json response.
+func (re *ResponseEntity) Problem() (*ProblemDetails, bool) {
+	mediaType, _, err := mime.ParseMediaType(re.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/problem+json" {
+		return nil, false
+	}
+
+	problem := &ProblemDetails{}
+	if err := json.Unmarshal(re.Body, problem); err != nil {
+		return nil, false
+	}
+	if problem.Status == 0 {
+		problem.Status = re.StatusCode
+	}
+	return problem, true
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func problemTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.",` +
+			`"detail":"Your current balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","balance":30}`))
+	}))
+}
+
+func TestShouldParseProblemDetails(t *testing.T) {
+	ts := problemTestServer()
+	defer ts.Close()
+
+	re, err := New().Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	problem, ok := re.Problem()
+	if !ok {
+		t.Fatal("Expected problem details")
+	}
+	assertBody(t, problem.Type, "https://example.com/probs/out-of-credit")
+	assertBody(t, problem.Title, "You do not have enough credit.")
+	assertBody(t, problem.Instance, "/account/12345/msgs/abc")
+	assertStatusCode(t, problem.Status, http.StatusForbidden)
+
+	var balance int
+	json.Unmarshal(problem.Extensions["balance"], &balance)
+	if balance != 30 {
+		t.Errorf("Expected balance: [%v] got: [%v]", 30, balance)
+	}
+
+	re = ResponseEntity{StatusCode: http.StatusBadRequest, Header: http.Header{"Content-Type": []string{"application/problem+json"}}, Body: []byte(`{"status":"400"}`)}
+	if problem, ok := re.Problem(); !ok || problem.Type != "about:blank" || problem.Status != http.StatusBadRequest {
+		t.Errorf("Expected about:blank problem with status 400 got: [%+v]", problem)
+	}
+	re.Header.Set("Content-Type", "application/json")
+	if _, ok := re.Problem(); ok {
+		t.Error("Expected no problem details for application/json")
+	}
+}
+
+func TestShouldReturnProblemDetailsAsError(t *testing.T) {
+	ts := problemTestServer()
+	defer ts.Close()
+
+	re, err := New(WithProblemDetails()).Get(ts.URL, JSONRequestCallback)
+	var problem *ProblemDetails
+	if !errors.As(err, &problem) || problem.Type != "https://example.com/probs/out-of-credit" {
+		t.Errorf("Expected problem details error got: [%v]", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusForbidden)
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// ProtobufRequestCallback sets the headers of a request exchanging application/x-protobuf.
+func ProtobufRequestCallback(r *http.Request) {
+	r.Header.Add("Accept", "application/x-protobuf")
+	r.Header.Add("Content-Type", "application/x-protobuf")
+	r.Header.Add("Cache-Control", "no-cache")
+}
+
+// EncodeProtobuf returns the protobuf encoding of the message m in a reader. Messages with their own
+// Marshal method are encoded with it, others with the codec registered for application/x-protobuf,
+// e.g. wrapping proto.Marshal.
+func EncodeProtobuf(m interface{}) (io.Reader, error) {
+	if marshaler, ok := m.(interface{ Marshal() ([]byte, error) }); ok {
+		b, err := marshaler.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(b), nil
+	}
+	return encodeCodec("application/x-protobuf", m)
+}
+
+// DecodeProtobuf decodes the protobuf encoded b into the message m, with its own Unmarshal method or
+// else the codec registered for application/x-protobuf.
+func DecodeProtobuf(b []byte, m interface{}) error {
+	if unmarshaler, ok := m.(interface{ Unmarshal([]byte) error }); ok {
+		return unmarshaler.Unmarshal(b)
+	}
+	return decodeCodec("application/x-protobuf", b, m)
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// protoGreeting is a hand written message with a single string field 1, as generated code would marshal it.
+type protoGreeting struct {
+	Text string
+}
+
+func (m *protoGreeting) Marshal() ([]byte, error) {
+	return append([]byte{0x0a, byte(len(m.Text))}, m.Text...), nil
+}
+
+func (m *protoGreeting) Unmarshal(b []byte) error {
+	if len(b) < 2 || b[0] != 0x0a || int(b[1]) != len(b)-2 {
+		return errors.New("invalid greeting")
+	}
+	m.Text = string(b[2:])
+	return nil
+}
+
+type protoReflected struct {
+	Text string
+}
+
+func TestShouldExchangeProtobuf(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in protoGreeting
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := in.Unmarshal(b); err != nil || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		out, _ := (&protoGreeting{Text: "hello " + in.Text}).Marshal()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Write(out)
+	}))
+	defer ts.Close()
+
+	body, err := EncodeProtobuf(&protoGreeting{Text: "jose"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	re, err := New().Post(ts.URL, body, ProtobufRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+
+	var greeting protoGreeting
+	if err := DecodeProtobuf(re.Body, &greeting); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, greeting.Text, "hello jose")
+}
+
+func TestShouldUseRegisteredProtobufCodec(t *testing.T) {
+	if _, err := EncodeProtobuf(&protoReflected{}); err == nil {
+		t.Error("Expected an error without a protobuf codec")
+	}
+
+	registerTestCodec(t, "application/x-protobuf", Codec{
+		Marshal: func(v interface{}) ([]byte, error) {
+			return (&protoGreeting{Text: v.(*protoReflected).Text}).Marshal()
+		},
+		Unmarshal: func(b []byte, v interface{}) error {
+			var m protoGreeting
+			err := m.Unmarshal(b)
+			v.(*protoReflected).Text = m.Text
+			return err
+		},
+	})
+
+	body, err := EncodeProtobuf(&protoReflected{Text: "jose"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	b, _ := ioutil.ReadAll(body)
+	var m protoReflected
+	if err := DecodeProtobuf(b, &m); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, m.Text, "jose")
+}
@@ -0,0 +1,42 @@
+package rest
+
+import "net/http"
+
+// WithHTTP2 attempts HTTP/2 over TLS, negotiated through ALPN. Servers that don't support it are still
+// reached over HTTP/1.1, flagged by ResponseEntity.ProtocolDowngraded.
+func WithHTTP2() Option {
+	return func(c *Client) {
+		c.http2 = true
+		c.protocols = nil
+	}
+}
+
+// WithH2C forces HTTP/2: cleartext backends are spoken to with prior knowledge (h2c), and TLS servers
+// that don't negotiate HTTP/2 fail.
+func WithH2C() Option {
+	return func(c *Client) {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		c.http2 = true
+		c.protocols = protocols
+	}
+}
+
+// WithoutHTTP2 only ever speaks HTTP/1.1, even with servers offering HTTP/2.
+func WithoutHTTP2() Option {
+	return func(c *Client) {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		c.http2 = false
+		c.protocols = protocols
+	}
+}
+
+// WithHTTP2Config tunes the HTTP/2 connections of the built transport, e.g. their flow control windows
+// and ping timeouts.
+func WithHTTP2Config(config http.HTTP2Config) Option {
+	return func(c *Client) {
+		c.http2Config = &config
+	}
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldNegotiateHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	re, err := New(WithHTTP2(), withTestServerTLS(ts)).Get(ts.URL, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if re.Proto != "HTTP/2.0" || re.ProtocolDowngraded {
+		t.Errorf("Expected protocol: [%v] got: [%v] downgraded: [%v]", "HTTP/2.0", re.Proto, re.ProtocolDowngraded)
+	}
+}
+
+func TestShouldFlagProtocolDowngrade(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	re, err := New(WithHTTP2(), withTestServerTLS(ts)).Get(ts.URL, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if re.Proto != "HTTP/1.1" || !re.ProtocolDowngraded {
+		t.Errorf("Expected protocol: [%v] got: [%v] downgraded: [%v]", "HTTP/1.1", re.Proto, re.ProtocolDowngraded)
+	}
+}
+
+// withTestServerTLS trusts the certificate of the given TLS test server.
+func withTestServerTLS(ts *httptest.Server) Option {
+	return WithTLSConfig(ts.Client().Transport.(*http.Transport).TLSClientConfig)
+}
+
+func TestShouldSpeakH2C(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetHTTP1(true)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+	defer ts.Close()
+
+	re, err := New(WithH2C()).Get(ts.URL, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "HTTP/2.0")
+
+	re, err = New().Get(ts.URL, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "HTTP/1.1")
+}
+
+func TestShouldDisableHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	re, err := New(WithHTTP2(), WithoutHTTP2(), withTestServerTLS(ts)).Get(ts.URL, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if re.Proto != "HTTP/1.1" || re.ProtocolDowngraded {
+		t.Errorf("Expected protocol: [%v] got: [%v] downgraded: [%v]", "HTTP/1.1", re.Proto, re.ProtocolDowngraded)
+	}
+}
+
+func TestShouldApplyHTTP2Config(t *testing.T) {
+	c := New(WithHTTP2Config(http.HTTP2Config{MaxConcurrentStreams: 10}))
+	transport := c.roundTripper().(*http.Transport)
+	if transport.HTTP2 == nil || transport.HTTP2.MaxConcurrentStreams != 10 {
+		t.Errorf("Expected HTTP/2 config got: [%+v]", transport.HTTP2)
+	}
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestShouldSendThroughProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proxy-Authorization", r.Header.Get("Proxy-Authorization"))
+		w.Write([]byte(r.URL.String()))
+	}))
+	defer proxy.Close()
+
+	u, _ := url.Parse(proxy.URL)
+	u.User = url.UserPassword("jose", "secret")
+	re, err := New(WithProxyURL(u)).Get("http://api.example.com/users?page=2", JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), "http://api.example.com/users?page=2")
+	assertHeader(t, re.Header, "X-Proxy-Authorization", "Basic am9zZTpzZWNyZXQ=")
+}
+
+func TestShouldUseProxyFromEnvironment(t *testing.T) {
+	if New().roundTripper().(*http.Transport).Proxy != nil {
+		t.Error("Expected no proxy by default")
+	}
+	if New(WithProxyFromEnvironment()).roundTripper().(*http.Transport).Proxy == nil {
+		t.Error("Expected proxy from environment")
+	}
+}
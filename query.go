@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"net/url"
+	"strings"
+)
+
+// EncodeQuery flattens the struct or map v into query values, like EncodeForm but for `query` struct tags.
+func EncodeQuery(v interface{}) (url.Values, error) {
+	return encodeValues(v, "query")
+}
+
+// AppendQuery adds the query values encoded from v (see EncodeQuery) to the query string of rawURL,
+// keeping its existing parameters, e.g. AppendQuery("/users?active=true", Page{Number: 2}).
+func AppendQuery(rawURL string, v interface{}) (string, error) {
+	values, err := EncodeQuery(v)
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 {
+		return rawURL, nil
+	}
+
+	fragment := ""
+	if i := strings.IndexByte(rawURL, '#'); i >= 0 {
+		rawURL, fragment = rawURL[:i], rawURL[i:]
+	}
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+		if strings.HasSuffix(rawURL, "?") || strings.HasSuffix(rawURL, "&") {
+			separator = ""
+		}
+	}
+	return rawURL + separator + values.Encode() + fragment, nil
+}
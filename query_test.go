@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"testing"
+)
+
+type queryTestParams struct {
+	Page   int      `query:"page"`
+	Search string   `query:"q,omitempty"`
+	Tags   []string `query:"tag"`
+	Secret string   `query:"-"`
+}
+
+func TestShouldEncodeQuery(t *testing.T) {
+	values, err := EncodeQuery(queryTestParams{Page: 2, Tags: []string{"a b", "c&d"}, Secret: "x"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if encoded := values.Encode(); encoded != "page=2&tag=a+b&tag=c%26d" {
+		t.Errorf("Expected query: [%v] got: [%v]", "page=2&tag=a+b&tag=c%26d", encoded)
+	}
+}
+
+func TestShouldAppendQuery(t *testing.T) {
+	cases := map[string]string{
+		"/users":             "/users?page=3&q=jose",
+		"/users?active=true": "/users?active=true&page=3&q=jose",
+		"/users?":            "/users?page=3&q=jose",
+		"/users#top":         "/users?page=3&q=jose#top",
+	}
+	for rawURL, expected := range cases {
+		target, err := AppendQuery(rawURL, map[string]interface{}{"page": 3, "q": "jose"})
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		if target != expected {
+			t.Errorf("Expected URL for %q: [%v] got: [%v]", rawURL, expected, target)
+		}
+	}
+}
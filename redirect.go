@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+)
+
+// sensitiveRedirectHeaders are the headers http.Client drops when a redirect leaves the original host.
+var sensitiveRedirectHeaders = []string{"Authorization", "Www-Authenticate", "Cookie", "Cookie2"}
+
+// WithForwardAuthOnRedirect keeps the sensitive headers (Authorization, Cookie, ...) on redirects to the given
+// hosts, which http.Client would otherwise drop once a redirect leaves the original host. Hosts match either
+// "host" or "host:port". Headers still dropped are reported in ResponseEntity.StrippedHeaders.
+func WithForwardAuthOnRedirect(allowedHosts ...string) Option {
+	return func(c *Client) {
+		allowed := make(map[string]bool, len(c.forwardAuthHosts)+len(allowedHosts))
+		for host := range c.forwardAuthHosts {
+			allowed[host] = true
+		}
+		for _, host := range allowedHosts {
+			allowed[host] = true
+		}
+		c.forwardAuthHosts = allowed
+	}
+}
+
+// checkRedirect returns a CheckRedirect with http.Client's default limit of 10 redirects that restores the
+// sensitive headers dropped on the way to an allowed host, and appends any others dropped to stripped.
+func (c *Client) checkRedirect(stripped *[]string) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		allowed := c.forwardAuthHosts[req.URL.Host] || c.forwardAuthHosts[req.URL.Hostname()]
+		for _, key := range sensitiveRedirectHeaders {
+			values, ok := via[0].Header[key]
+			if _, kept := req.Header[key]; !ok || kept {
+				continue
+			}
+			if allowed {
+				req.Header[key] = append([]string(nil), values...)
+				continue
+			}
+			if !containsString(*stripped, key) {
+				*stripped = append(*stripped, key)
+			}
+		}
+		return nil
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestShouldForwardAuthOnRedirectToAllowedHost(t *testing.T) {
+	target := authEchoTestServer()
+	defer target.Close()
+	ts := crossHostRedirectTestServer(target)
+	defer ts.Close()
+
+	c := New(WithForwardAuthOnRedirect("localhost"))
+	re, err := c.Get(ts.URL+"/redirect", authorizationCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), "Bearer token")
+	if len(re.StrippedHeaders) != 0 {
+		t.Errorf("Expected no stripped headers got: [%v]", re.StrippedHeaders)
+	}
+}
+
+func TestShouldStripAuthOnRedirectToOtherHost(t *testing.T) {
+	target := authEchoTestServer()
+	defer target.Close()
+	ts := crossHostRedirectTestServer(target)
+	defer ts.Close()
+
+	c := New(WithForwardAuthOnRedirect("api.example.com"))
+	re, err := c.Get(ts.URL+"/redirect", authorizationCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), "")
+	if strings.Join(re.StrippedHeaders, ",") != "Authorization" {
+		t.Errorf("Expected stripped headers: [%v] got: [%v]", "Authorization", re.StrippedHeaders)
+	}
+}
+
+func authorizationCallback(r *http.Request) {
+	r.Header.Set("Authorization", "Bearer token")
+}
+
+func authEchoTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+}
+
+// crossHostRedirectTestServer redirects to target addressed as localhost, a different host than 127.0.0.1.
+func crossHostRedirectTestServer(target *httptest.Server) *httptest.Server {
+	u, _ := url.Parse(target.URL)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost:"+u.Port()+"/target", http.StatusFound)
+	}))
+}
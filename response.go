@@ -0,0 +1,233 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Age returns how long the response has been cached upstream, as reported by the Age header.
+func (re *ResponseEntity) Age() (time.Duration, bool) {
+	value := strings.TrimSpace(re.Header.Get("Age"))
+	if len(value) == 0 {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// DecodeWith decodes the body into the value pointed to by v using the given decode function,
+// e.g. a CBOR or protobuf unmarshaller. A 204 or any other 2xx with an empty body sets v to its zero value.
+func (re *ResponseEntity) DecodeWith(d func([]byte, interface{}) error, v interface{}) error {
+	if re.noContent() {
+		setZero(v)
+		return nil
+	}
+	return d(re.Body, v)
+}
+
+// Decode decodes the body into the value pointed to by v with the codec registered for its Content-Type,
+// see RegisterCodec. Structured syntax suffixes like +json and +xml pick the JSON and XML codecs, and
+// bodies of other or missing types are decoded as JSON. A response without content sets v to its zero value.
+func (re *ResponseEntity) Decode(v interface{}) error {
+	if re.noContent() {
+		setZero(v)
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(re.Header.Get("Content-Type"))
+	if mediaType == "application/x-protobuf" {
+		return DecodeProtobuf(re.Body, v)
+	}
+	if codec, ok := CodecFor(mediaType); ok && codec.Unmarshal != nil {
+		return codec.Unmarshal(re.Body, v)
+	}
+	if strings.HasSuffix(mediaType, "+xml") {
+		return DecodeXML(re.Body, v)
+	}
+	return DecodeJSON(re.Body, v)
+}
+
+// noContent reports whether the response is a 204 No Content or another 2xx with an empty body.
+func (re *ResponseEntity) noContent() bool {
+	if re.StatusCode == http.StatusNoContent {
+		return true
+	}
+	return re.StatusCode >= 200 && re.StatusCode < 300 && len(bytes.TrimSpace(re.Body)) == 0
+}
+
+// setZero sets the value pointed to by v to its zero value.
+func setZero(v interface{}) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	}
+}
+
+// Warning struct represents a value of the Warning header.
+type Warning struct {
+	Code  int
+	Agent string
+	Text  string
+}
+
+// Warnings returns the parsed Warning headers, e.g. the staleness notices added by caches.
+func (re *ResponseEntity) Warnings() []Warning {
+	var warnings []Warning
+	for _, value := range re.Header.Values("Warning") {
+		for len(value) > 0 {
+			var warning Warning
+			var ok bool
+			if warning, value, ok = parseWarning(value); ok {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	return warnings
+}
+
+// parseWarning parses the first warning-value of s and returns the remainder after it.
+func parseWarning(s string) (Warning, string, bool) {
+	s = strings.TrimLeft(s, " ,")
+	fields := strings.SplitN(s, " ", 3)
+	if len(fields) < 3 {
+		return Warning{}, "", false
+	}
+
+	code, err := strconv.Atoi(fields[0])
+	text, rest, ok := parseQuoted(strings.TrimLeft(fields[2], " "))
+	if !ok {
+		return Warning{}, "", false
+	}
+
+	// skip the optional warn-date
+	rest = strings.TrimLeft(rest, " ")
+	if strings.HasPrefix(rest, "\"") {
+		_, rest, _ = parseQuoted(rest)
+	}
+	return Warning{Code: code, Agent: fields[1], Text: text}, rest, err == nil
+}
+
+// parseQuoted parses the quoted-string at the start of s and returns the remainder after it.
+func parseQuoted(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "\"") {
+		return "", "", false
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", false
+}
+
+// Lines returns the body split into lines, without line terminators.
+func (re *ResponseEntity) Lines() []string {
+	var lines []string
+	re.ForEachLine(func(line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	return lines
+}
+
+// ForEachLine calls fn for every line of the body, stopping at the first error fn returns.
+func (re *ResponseEntity) ForEachLine(fn func(line string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(re.Body))
+	scanner.Buffer(make([]byte, 0, 4096), len(re.Body)+1)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// UnwrapJSON decodes the value under the top-level key of a JSON envelope like {"data": {...}} into v.
+// A response without content sets v to its zero value.
+func (re *ResponseEntity) UnwrapJSON(key string, v interface{}) error {
+	if re.noContent() {
+		setZero(v)
+		return nil
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := DecodeJSON(re.Body, &envelope); err != nil {
+		return err
+	}
+
+	raw, ok := envelope[key]
+	if !ok {
+		return fmt.Errorf("rest: JSON envelope has no %q key", key)
+	}
+	return DecodeJSON(raw, v)
+}
+
+// Server returns the Server header, naming the software that served the response.
+func (re *ResponseEntity) Server() string {
+	return re.Header.Get("Server")
+}
+
+// Via returns the proxies the response went through, one entry per hop as listed in the Via headers.
+func (re *ResponseEntity) Via() []string {
+	var hops []string
+	for _, value := range re.Header.Values("Via") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); len(hop) > 0 {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// ToHTTPResponse rebuilds a standard *http.Response from the buffered response, for code expecting one.
+func (re *ResponseEntity) ToHTTPResponse() *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", re.StatusCode, http.StatusText(re.StatusCode)),
+		StatusCode:    re.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        re.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(re.Body)),
+		ContentLength: int64(len(re.Body)),
+	}
+}
+
+// Filename returns the file name suggested by the Content-Disposition header, decoding RFC 5987 filename*
+// values. Any directory part is stripped so the name is safe to use as a local file name.
+func (re *ResponseEntity) Filename() (string, bool) {
+	_, params, err := mime.ParseMediaType(re.Header.Get("Content-Disposition"))
+	if err != nil {
+		return "", false
+	}
+
+	name := path.Base(strings.ReplaceAll(params["filename"], "\\", "/"))
+	if name == "." || name == ".." || name == "/" || len(name) == 0 {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,260 @@
+package rest
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShouldParseAge(t *testing.T) {
+	re := ResponseEntity{Header: http.Header{"Age": []string{"120"}}}
+
+	age, ok := re.Age()
+	if !ok {
+		t.Error("Age header should be present")
+	}
+
+	if age != 2*time.Minute {
+		t.Errorf("Expected age: [%v] got: [%v]", 2*time.Minute, age)
+	}
+}
+
+func TestShouldNotParseAbsentAge(t *testing.T) {
+	re := ResponseEntity{Header: make(http.Header)}
+
+	if age, ok := re.Age(); ok || age != 0 {
+		t.Errorf("Expected no age got: [%v]", age)
+	}
+
+	re.Header.Set("Age", "not-a-number")
+	if _, ok := re.Age(); ok {
+		t.Error("Invalid Age header should not be parsed")
+	}
+}
+
+func TestShouldDecodeWithCustomDecoder(t *testing.T) {
+	re := ResponseEntity{Body: []byte("someProperty=someValue")}
+
+	decoded := map[string]string{}
+	err := re.DecodeWith(func(b []byte, v interface{}) error {
+		parts := strings.SplitN(string(b), "=", 2)
+		if len(parts) != 2 {
+			return errors.New("invalid pair")
+		}
+		v.(map[string]string)[parts[0]] = parts[1]
+		return nil
+	}, decoded)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if decoded["someProperty"] != "someValue" {
+		t.Errorf("Expected someValue got: [%v]", decoded)
+	}
+}
+
+func TestShouldParseWarnings(t *testing.T) {
+	re := ResponseEntity{Header: http.Header{"Warning": []string{
+		"110 anderson/1.3.37 \"Response is stale\"",
+		"112 - \"Disconnected, \\\"offline\\\" operation\" \"Wed, 21 Oct 2015 07:28:00 GMT\", 214 proxy.example.com \"Transformation applied\"",
+	}}}
+
+	expected := []Warning{
+		{Code: 110, Agent: "anderson/1.3.37", Text: "Response is stale"},
+		{Code: 112, Agent: "-", Text: "Disconnected, \"offline\" operation"},
+		{Code: 214, Agent: "proxy.example.com", Text: "Transformation applied"},
+	}
+	if warnings := re.Warnings(); !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected warnings: [%v] got: [%v]", expected, warnings)
+	}
+
+	if warnings := (&ResponseEntity{Header: make(http.Header)}).Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings got: [%v]", warnings)
+	}
+}
+
+func TestShouldSplitBodyLines(t *testing.T) {
+	re := ResponseEntity{Body: []byte("first\r\nsecond\n\nfourth\n")}
+
+	expected := []string{"first", "second", "", "fourth"}
+	if lines := re.Lines(); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected lines: [%q] got: [%q]", expected, lines)
+	}
+
+	if lines := (&ResponseEntity{}).Lines(); len(lines) != 0 {
+		t.Errorf("Expected no lines got: [%q]", lines)
+	}
+}
+
+func TestShouldStopForEachLineOnError(t *testing.T) {
+	re := ResponseEntity{Body: []byte("id,name\n1,first\n2,second")}
+
+	stop := errors.New("stop")
+	var seen []string
+	err := re.ForEachLine(func(line string) error {
+		seen = append(seen, line)
+		if strings.HasPrefix(line, "1,") {
+			return stop
+		}
+		return nil
+	})
+
+	if err != stop {
+		t.Errorf("Expected error: [%v] got: [%v]", stop, err)
+	}
+
+	if len(seen) != 2 {
+		t.Errorf("Expected to stop after 2 lines got: [%q]", seen)
+	}
+}
+
+func TestShouldUnwrapJSONEnvelope(t *testing.T) {
+	re := ResponseEntity{Body: []byte("{\"data\":{\"user\":{\"name\":\"jose\",\"roles\":[\"admin\"]}},\"meta\":{\"page\":1}}")}
+
+	data := struct {
+		User struct {
+			Name  string
+			Roles []string
+		}
+	}{}
+	if err := re.UnwrapJSON("data", &data); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if data.User.Name != "jose" || len(data.User.Roles) != 1 {
+		t.Errorf("Expected nested user got: [%+v]", data)
+	}
+
+	if err := re.UnwrapJSON("missing", &data); err == nil {
+		t.Error("Expected error for missing key")
+	}
+}
+
+func TestShouldReadServerAndVia(t *testing.T) {
+	re := ResponseEntity{Header: http.Header{
+		"Server": {"nginx/1.19.0"},
+		"Via":    {"1.1 varnish (Varnish/6.0), 1.1 edge.example.com", "2 cdn"},
+	}}
+
+	if server := re.Server(); server != "nginx/1.19.0" {
+		t.Errorf("Expected server: [%v] got: [%v]", "nginx/1.19.0", server)
+	}
+
+	expected := []string{"1.1 varnish (Varnish/6.0)", "1.1 edge.example.com", "2 cdn"}
+	if via := re.Via(); !reflect.DeepEqual(via, expected) {
+		t.Errorf("Expected via: [%q] got: [%q]", expected, via)
+	}
+
+	empty := ResponseEntity{Header: make(http.Header)}
+	if empty.Server() != "" || len(empty.Via()) != 0 {
+		t.Errorf("Expected no server nor via got: [%v] [%v]", empty.Server(), empty.Via())
+	}
+}
+
+func TestShouldConvertToHTTPResponse(t *testing.T) {
+	re := ResponseEntity{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte("{\"someProperty\":\"someValue\"}"),
+	}
+
+	res := re.ToHTTPResponse()
+	defer res.Body.Close()
+
+	assertStatusCode(t, res.StatusCode, http.StatusCreated)
+	if res.Status != "201 Created" || res.ContentLength != int64(len(re.Body)) {
+		t.Errorf("Unexpected status line or length: [%v] [%v]", res.Status, res.ContentLength)
+	}
+	assertHeader(t, res.Header, "Content-Type", "application/json")
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, string(body), re.BodyString())
+}
+
+func TestShouldParseFilename(t *testing.T) {
+	cases := map[string]string{
+		"attachment; filename=\"report.csv\"":                                    "report.csv",
+		"attachment; filename*=UTF-8''%E2%82%AC%20rates.csv":                     "€ rates.csv",
+		"attachment; filename=\"fallback.csv\"; filename*=UTF-8''na%C3%AFve.csv": "naïve.csv",
+		"attachment; filename=\"../../etc/passwd\"":                              "passwd",
+	}
+	for disposition, expected := range cases {
+		re := ResponseEntity{Header: http.Header{"Content-Disposition": {disposition}}}
+		if filename, ok := re.Filename(); !ok || filename != expected {
+			t.Errorf("Expected filename for %v: [%v] got: [%v]", disposition, expected, filename)
+		}
+	}
+
+	for _, disposition := range []string{"", "inline", "attachment; filename=\"..\""} {
+		re := ResponseEntity{Header: http.Header{"Content-Disposition": {disposition}}}
+		if filename, ok := re.Filename(); ok {
+			t.Errorf("Expected no filename for %v got: [%v]", disposition, filename)
+		}
+	}
+}
+
+func TestShouldUnwrapJSONZeroValueOnEmptyBody(t *testing.T) {
+	re := ResponseEntity{StatusCode: http.StatusOK, Body: []byte{}}
+
+	data := []string{"stale"}
+	if err := re.UnwrapJSON("data", &data); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if data != nil {
+		t.Errorf("Expected zero value got: [%v]", data)
+	}
+}
+
+func TestShouldDecodeByContentType(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name"`
+	}
+	msgpack, _ := ioutil.ReadAll(mustEncodeMessagePack(t, user{Name: "msgpack"}))
+	proto, _ := (&protoGreeting{Text: "proto"}).Marshal()
+
+	cases := map[string]string{
+		"json":    "application/json; charset=utf-8",
+		"hal":     "application/hal+json",
+		"xml":     "text/xml",
+		"atom":    "application/atom+xml",
+		"msgpack": "application/msgpack",
+		"default": "",
+	}
+	for name, contentType := range cases {
+		body := []byte(`{"name":"` + name + `"}`)
+		if strings.HasSuffix(contentType, "xml") {
+			body = []byte("<user><name>" + name + "</name></user>")
+		} else if name == "msgpack" {
+			body = msgpack
+		}
+
+		re := ResponseEntity{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{contentType}}, Body: body}
+		var u user
+		if err := re.Decode(&u); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		if u.Name != name {
+			t.Errorf("Expected name for [%v]: [%v] got: [%v]", contentType, name, u.Name)
+		}
+	}
+
+	re := ResponseEntity{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"application/x-protobuf"}}, Body: proto}
+	var greeting protoGreeting
+	if err := re.Decode(&greeting); err != nil || greeting.Text != "proto" {
+		t.Errorf("Expected greeting: [proto] got: [%v] [%v]", greeting.Text, err)
+	}
+
+	u := user{Name: "stale"}
+	re = ResponseEntity{StatusCode: http.StatusNoContent, Header: make(http.Header)}
+	if err := re.Decode(&u); err != nil || u.Name != "" {
+		t.Errorf("Expected zero value got: [%v] [%v]", u, err)
+	}
+}
@@ -3,27 +3,108 @@ package rest
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
 	"strings"
 	"time"
 )
 
 // ResponseEntity struct represents a HTTP response.
 type ResponseEntity struct {
-	StatusCode int
-	Header     http.Header
-	Body       []byte
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	Timing      Timing
+	SentHeaders http.Header
+	FromCache   bool
+	Proto       string
+
+	// ProtocolDowngraded reports that HTTP/2 was asked for with WithHTTP2 but an older protocol was negotiated.
+	ProtocolDowngraded bool
+
+	// StrippedHeaders lists the sensitive headers dropped while following redirects to other hosts.
+	StrippedHeaders []string
 }
 
 type Client struct {
+	ctx                   context.Context
+	gzipRequestHosts      map[string]bool
+	verifyDigest          bool
+	sizeTimeout           *sizeBasedTimeout
+	retry                 retryPolicy
+	classifier            func(re *ResponseEntity, err error) ErrorClass
+	idempotencyKeys       IdempotencyKeyStore
+	dialRetries           int
+	dialBackoff           time.Duration
+	cache                 *responseCache
+	requireContentType    bool
+	metricsHook           func(m RequestMetrics)
+	operation             string
+	stats                 *clientStats
+	header                http.Header
+	dryRun                func(*http.Request)
+	optionsTimeout        time.Duration
+	propagateTraceContext bool
+	sniffCompression      bool
+	defaultBody           []byte
+	paginationDeadline    time.Duration
+	maxPages              int
+	urlRewriter           func(string) (string, error)
+	forwardAuthHosts      map[string]bool
+	http2                 bool
+	tlsConfig             *tls.Config
+	timeout               time.Duration
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	transport             http.RoundTripper
+	client                *http.Client
+	breaker               *circuitBreaker
+	middleware            []Middleware
+	baseURL               string
+	tokenProvider         TokenProvider
+	query                 url.Values
+	proxy                 func(*http.Request) (*url.URL, error)
+	unixSocket            string
+	protocols             *http.Protocols
+	http2Config           *http.HTTP2Config
+	http3                 func(*tls.Config) http.RoundTripper
+	problemErrors         bool
+	tlsErr                error
 }
 
-func New() *Client {
-	return &Client{}
+// Option configures a Client.
+type Option func(*Client)
+
+func New(opts ...Option) *Client {
+	c := &Client{stats: newClientStats(DefaultLatencyBuckets)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// With returns a copy of the Client with the given options applied on top, e.g. to tag a single call
+// with WithOperationName. The copy shares caches and stores with the original.
+func (c *Client) With(opts ...Option) *Client {
+	cc := *c
+	for _, opt := range opts {
+		opt(&cc)
+	}
+	return &cc
+}
+
+// FromContext returns a Client whose requests are bound to ctx and whose timeout never outlives ctx's deadline.
+func FromContext(ctx context.Context, opts ...Option) *Client {
+	c := New(opts...)
+	c.ctx = ctx
+	return c
 }
 
 // BodyString resturns a ResponseEntity body as string.
@@ -31,8 +112,26 @@ func (re *ResponseEntity) BodyString() string {
 	return string(re.Body)
 }
 
+// Timeout returns the request timeout, 10s unless set with WithTimeout, capped by the remaining time
+// of the bound context.
 func (c *Client) Timeout() time.Duration {
-	return 10 * time.Second
+	timeout := 10 * time.Second
+	if c.timeout > 0 {
+		timeout = c.timeout
+	}
+	if deadline, ok := c.context().Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			return remaining
+		}
+	}
+	return timeout
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
 }
 
 func (c *Client) TransportTimeout() time.Duration {
@@ -40,52 +139,210 @@ func (c *Client) TransportTimeout() time.Duration {
 }
 
 func (c *Client) NewHTTPClient() *http.Client {
-	var transport = &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: c.TransportTimeout(),
-		}).Dial,
-		TLSHandshakeTimeout: c.TransportTimeout(),
-	}
 	return &http.Client{
 		Timeout:   c.Timeout(),
-		Transport: transport,
+		Transport: c.roundTripper(),
 	}
 }
 
+// roundTripper returns the transport set with WithTransport, or builds one from the Client's options.
+func (c *Client) roundTripper() http.RoundTripper {
+	if c.transport != nil {
+		return c.transport
+	}
+
+	dialTimeout, tlsHandshakeTimeout := c.TransportTimeout(), c.TransportTimeout()
+	if c.dialTimeout > 0 {
+		dialTimeout = c.dialTimeout
+	}
+	if c.tlsHandshakeTimeout > 0 {
+		tlsHandshakeTimeout = c.tlsHandshakeTimeout
+	}
+
+	var dial dialFunc = (&net.Dialer{
+		Timeout: dialTimeout,
+	}).DialContext
+	if len(c.unixSocket) > 0 {
+		dial = dialUnix(dial, c.unixSocket)
+	}
+	if c.dialRetries > 0 {
+		dial = retryDial(dial, c.dialRetries, c.dialBackoff)
+	}
+	var transport = &http.Transport{
+		DialContext:         dial,
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+		ForceAttemptHTTP2:   c.http2,
+		Proxy:               c.proxy,
+	}
+	if c.tlsConfig != nil {
+		transport.TLSClientConfig = c.tlsConfig.Clone()
+	}
+	if c.protocols != nil {
+		protocols := *c.protocols
+		transport.Protocols = &protocols
+	}
+	transport.HTTP2 = c.http2Config
+	if c.http3 != nil {
+		return &http3RoundTripper{http3: c.http3(c.tlsConfig.Clone()), fallback: transport}
+	}
+	return transport
+}
+
+// httpClient returns the client used by exchange, whose deadline is carried by the request context instead.
+// A client set with WithHTTPClient is copied as is, keeping its own Timeout. Middleware wraps either transport.
+func (c *Client) httpClient() *http.Client {
+	if c.client != nil {
+		client := *c.client
+		client.Transport = c.chain(client.Transport)
+		return &client
+	}
+	client := c.NewHTTPClient()
+	client.Timeout = 0
+	client.Transport = c.chain(client.Transport)
+	return client
+}
+
 func JSONRequestCallback(r *http.Request) {
 	r.Header.Add("Accept", "application/json")
 	r.Header.Add("Content-Type", "application/json")
 	r.Header.Add("Cache-Control", "no-cache")
 }
 
-func exchange(client *http.Client, timeout time.Duration, url, method string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
-	ctx := context.Background()
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+func (c *Client) exchange(url, method string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	req, err := c.newRequest(url, method, body)
+	if err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+
+	ctx, cancel := context.WithTimeout(c.context(), c.requestTimeout(req))
 	defer cancel()
+	req = req.WithContext(ctx)
+
+	if err := c.prepareRequest(req, requestCallback); err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+
+	if cached, ok := c.cache.lookup(req); ok {
+		return cached, nil
+	}
 
-	req, err := http.NewRequest(method, url, body)
+	operation, err := c.applyIdempotencyKey(req)
 	if err != nil {
 		return ResponseEntity{Header: make(http.Header)}, err
 	}
 
-	req = req.WithContext(ctx)
+	if err := c.breaker.allow(req.URL.Host); err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
 
+	re, err := c.sendWithRetry(req)
+	c.recordBreaker(req.URL.Host, &re, err)
+	re = c.cache.update(req, re, err)
+	if releaseErr := c.releaseIdempotencyKey(operation, re, err); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	if err == nil && c.problemErrors {
+		if problem, ok := re.Problem(); ok {
+			return re, problem
+		}
+	}
+	return re, err
+}
+
+// newRequest builds the request for url against the Client's base URL, with the default body for method
+// when body is nil.
+func (c *Client) newRequest(url, method string, body io.Reader) (*http.Request, error) {
+	if c.tlsErr != nil {
+		return nil, c.tlsErr
+	}
+	url, err := c.targetURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		body = c.defaultRequestBody(method)
+	}
+	return http.NewRequestWithContext(c.context(), method, url, body)
+}
+
+// prepareRequest compresses the body of req and applies the default headers and query, credentials,
+// the request callback and the trace context, in that order.
+func (c *Client) prepareRequest(req *http.Request, requestCallback func(r *http.Request)) error {
+	if err := c.compressRequest(req); err != nil {
+		return err
+	}
+
+	c.applyDefaultHeaders(req)
+	c.applyDefaultQuery(req)
+	if err := c.applyBearerToken(req); err != nil {
+		return err
+	}
 	if requestCallback != nil {
 		requestCallback(req)
 	}
+	c.applyTraceContext(req)
+	return nil
+}
+
+// send performs a single attempt of the given request.
+func (c *Client) send(req *http.Request) (ResponseEntity, error) {
+	tt := newTimingTrace()
+	wt := &writeTrace{}
+	ctx := httptrace.WithClientTrace(req.Context(), tt.clientTrace())
+	req = req.WithContext(httptrace.WithClientTrace(ctx, wt.clientTrace()))
+	if c.verifyDigest && len(req.Header.Get("Accept-Encoding")) == 0 {
+		// asking for gzip ourselves stops the transport from transparently decoding the body before it's hashed
+		req.Header = req.Header.Clone()
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	sentHeaders := req.Header.Clone()
+
+	if c.dryRun != nil {
+		c.dryRun(req)
+		return ResponseEntity{StatusCode: http.StatusOK, Header: make(http.Header), Body: []byte{}, SentHeaders: sentHeaders}, nil
+	}
 
+	var stripped []string
+	client := c.httpClient()
+	if client.CheckRedirect == nil {
+		client.CheckRedirect = c.checkRedirect(&stripped)
+	}
 	res, err := client.Do(req)
 	if err != nil {
+		if wt.failed() {
+			err = &writeError{err: err}
+		}
 		return ResponseEntity{Header: make(http.Header)}, err
 	}
 
 	defer res.Body.Close()
+	tt.bodyReadStarted()
 	resBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return ResponseEntity{Header: make(http.Header)}, err
+		return ResponseEntity{Header: make(http.Header)}, &bodyReadError{err: err}
 	}
 
-	return ResponseEntity{StatusCode: res.StatusCode, Header: res.Header, Body: resBody}, nil
+	re := ResponseEntity{StatusCode: res.StatusCode, Header: res.Header, Body: resBody, Timing: tt.done(), SentHeaders: sentHeaders, StrippedHeaders: stripped}
+	re.Proto = res.Proto
+	re.ProtocolDowngraded = c.http2 && res.ProtoMajor < 2
+	// digests cover the body as received, before any content coding is undone
+	if c.verifyDigest && !res.Uncompressed {
+		if err := verifyDigest(&re); err != nil {
+			return re, err
+		}
+	}
+	if err := decompressResponse(&re); err != nil {
+		return re, fmt.Errorf("rest: decompressing response: %w", err)
+	}
+	if c.sniffCompression {
+		sniffDecompress(&re)
+	}
+	if c.requireContentType {
+		if err := requireContentType(&re); err != nil {
+			return re, err
+		}
+	}
+	return re, nil
 }
 
 // EncodeJSON returns the JSON encoding of v in a reader
@@ -95,14 +352,56 @@ func EncodeJSON(v interface{}) io.Reader {
 	return w
 }
 
-// DecodeJSON decodes the JSON encoded b into the value pointed to by v.
+// DecodeJSON decodes the JSON encoded b into the value pointed to by v, with the same semantics as json.Unmarshal.
 func DecodeJSON(b []byte, v interface{}) error {
-	return json.NewDecoder(bytes.NewReader(b)).Decode(&v)
+	return json.Unmarshal(b, v)
 }
 
-// Exchange generic function that exchanges/requests HTTP operations/verbs
+// DecodeJSONMapped decodes the JSON encoded b into the value pointed to by v, after renaming object keys
+// found in fieldMap (e.g. "user_name" to "userName") at any depth.
+func DecodeJSONMapped(b []byte, v interface{}, fieldMap map[string]string) error {
+	var raw interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&raw); err != nil {
+		return err
+	}
+
+	mapped, err := json.Marshal(renameJSONKeys(raw, fieldMap))
+	if err != nil {
+		return err
+	}
+	return DecodeJSON(mapped, v)
+}
+
+func renameJSONKeys(v interface{}, fieldMap map[string]string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(value))
+		for key, elem := range value {
+			if name, ok := fieldMap[key]; ok {
+				key = name
+			}
+			renamed[key] = renameJSONKeys(elem, fieldMap)
+		}
+		return renamed
+	case []interface{}:
+		for i, elem := range value {
+			value[i] = renameJSONKeys(elem, fieldMap)
+		}
+	}
+	return v
+}
+
+// Exchange generic function that exchanges/requests HTTP operations/verbs.
+// Errors are returned as *RequestError.
 func (c *Client) Exchange(url, method string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
-	return exchange(c.NewHTTPClient(), c.Timeout(), url, method, body, requestCallback)
+	start := time.Now()
+	re, err := c.exchange(url, method, body, requestCallback)
+	err = wrapRequestError(method, url, err)
+	c.stats.record(re.StatusCode, time.Since(start), err)
+	c.reportMetrics(method, url, start, re, err)
+	return re, err
 }
 
 // Get gets the content from the given URL
@@ -110,6 +409,16 @@ func (c *Client) Get(url string, requestCallback func(r *http.Request)) (Respons
 	return c.Exchange(url, http.MethodGet, nil, requestCallback)
 }
 
+// GetJSON gets the content from the given URL and decodes its JSON body into the value pointed to by v.
+// A 204 or an empty 2xx body sets v to its zero value.
+func (c *Client) GetJSON(url string, v interface{}, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	re, err := c.Get(url, requestCallback)
+	if err != nil {
+		return re, err
+	}
+	return re, re.DecodeWith(DecodeJSON, v)
+}
+
 // Head returns the headers from the given URL
 func (c *Client) Head(url string, requestCallback func(r *http.Request)) (http.Header, error) {
 	re, err := c.Exchange(url, http.MethodHead, nil, requestCallback)
@@ -131,9 +440,14 @@ func (c *Client) Patch(url string, body io.Reader, requestCallback func(r *http.
 	return c.Exchange(url, http.MethodPatch, body, requestCallback)
 }
 
+// Options requests the communication options available for the given URL
+func (c *Client) Options(url string, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return c.Exchange(url, http.MethodOptions, nil, requestCallback)
+}
+
 // OptionsForAllow returns the allowed HTTP methods
 func (c *Client) OptionsForAllow(url string, requestCallback func(r *http.Request)) ([]string, error) {
-	re, err := c.Exchange(url, http.MethodOptions, nil, requestCallback)
+	re, err := c.Options(url, requestCallback)
 	allowHeader := re.Header.Get("Allow")
 	if len(allowHeader) > 0 {
 		return strings.Split(allowHeader, ","), err
@@ -1,13 +1,17 @@
 package rest
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/jattschneider/rest/resttest"
 )
 
 func TestShouldHead(t *testing.T) {
@@ -148,10 +152,9 @@ func testHandler(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("{\"someProperty\":\"someValue\"}"))
 	case http.MethodPatch, http.MethodPost, http.MethodPut:
-		rBody, err := ioutil.ReadAll(r.Body)
+		rBody, err := resttest.ReadRequestBody(r)
 		if err == nil {
-			w.WriteHeader(http.StatusOK)
-			w.Write(rBody)
+			resttest.WriteResponseBody(w, r, rBody)
 		}
 	default:
 		return
@@ -174,3 +177,185 @@ func assertHeader(t *testing.T, header http.Header, name, expected string) {
 func testServer() *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(testHandler))
 }
+
+func TestShouldFollowSeeOtherWithGet(t *testing.T) {
+	c := New()
+	ts := redirectTestServer(http.StatusSeeOther)
+	defer ts.Close()
+
+	re, err := c.Post(ts.URL+"/redirect", strings.NewReader("payload"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertBody(t, re.BodyString(), "GET:")
+}
+
+func TestShouldPreserveMethodOnTemporaryRedirect(t *testing.T) {
+	c := New()
+	ts := redirectTestServer(http.StatusTemporaryRedirect)
+	defer ts.Close()
+
+	re, err := c.Post(ts.URL+"/redirect", strings.NewReader("payload"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertBody(t, re.BodyString(), "POST:payload")
+}
+
+func TestShouldPreserveMethodOnPermanentRedirect(t *testing.T) {
+	c := New()
+	ts := redirectTestServer(http.StatusPermanentRedirect)
+	defer ts.Close()
+
+	re, err := c.Put(ts.URL+"/redirect", strings.NewReader("payload"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertBody(t, re.BodyString(), "PUT:payload")
+}
+
+func redirectTestServer(code int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/target", code)
+			return
+		}
+		rBody, _ := ioutil.ReadAll(r.Body)
+		w.Write([]byte(r.Method + ":" + string(rBody)))
+	}))
+}
+
+func assertBody(t *testing.T, body, expected string) {
+	if body != expected {
+		t.Errorf("Expected body: [%v] got: [%v]", expected, body)
+	}
+}
+
+func TestShouldBoundRequestByContextDeadline(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	c := FromContext(ctx)
+	if c.Timeout() > 100*time.Millisecond {
+		t.Errorf("Expected timeout bounded by context got: [%v]", c.Timeout())
+	}
+
+	start := time.Now()
+	_, err := c.Get(ts.URL, JSONRequestCallback)
+	if err == nil {
+		t.Error("Expected deadline error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Request outlived context deadline: [%v]", elapsed)
+	}
+}
+
+func TestShouldCaptureSentHeaders(t *testing.T) {
+	ts := gzipTestServer()
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	c := New(WithGzipRequestForHosts(u.Hostname()))
+
+	re, err := c.Post(ts.URL, strings.NewReader("{\"someProperty\":\"someValue\"}"), func(r *http.Request) {
+		JSONRequestCallback(r)
+		r.Header.Set("X-Request-Id", "42")
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.SentHeaders, "Content-Encoding", "gzip")
+	assertHeader(t, re.SentHeaders, "Accept", "application/json")
+	assertHeader(t, re.SentHeaders, "X-Request-Id", "42")
+}
+
+func TestShouldDecodeJSONMapped(t *testing.T) {
+	b := []byte("{\"user_name\":\"jose\",\"id\":9007199254740993,\"friends\":[{\"user_name\":\"maria\"}]}")
+
+	user := struct {
+		UserName string `json:"userName"`
+		ID       int64  `json:"id"`
+		Friends  []struct {
+			UserName string `json:"userName"`
+		} `json:"friends"`
+	}{}
+	if err := DecodeJSONMapped(b, &user, map[string]string{"user_name": "userName"}); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if user.UserName != "jose" || user.ID != 9007199254740993 {
+		t.Errorf("Expected mapped user got: [%+v]", user)
+	}
+
+	if len(user.Friends) != 1 || user.Friends[0].UserName != "maria" {
+		t.Errorf("Expected nested keys to be mapped got: [%+v]", user.Friends)
+	}
+}
+
+func TestShouldDecodeJSONLikeUnmarshal(t *testing.T) {
+	b := []byte("{\"someProperty\":\"someValue\"}")
+	type payload struct{ SomeProperty string }
+
+	var value payload
+	if err := DecodeJSON(b, &value); err != nil || value.SomeProperty != "someValue" {
+		t.Errorf("Expected *T to decode got: [%+v] [%v]", value, err)
+	}
+
+	var pointer *payload
+	if err := DecodeJSON(b, &pointer); err != nil || pointer == nil || pointer.SomeProperty != "someValue" {
+		t.Errorf("Expected **T to allocate and decode got: [%+v] [%v]", pointer, err)
+	}
+
+	existing := &payload{SomeProperty: "old"}
+	kept := existing
+	if err := DecodeJSON(b, &existing); err != nil || existing != kept || kept.SomeProperty != "someValue" {
+		t.Errorf("Expected **T to decode into the existing value got: [%+v] [%v]", existing, err)
+	}
+
+	var m map[string]string
+	if err := DecodeJSON(b, &m); err != nil || m["someProperty"] != "someValue" {
+		t.Errorf("Expected map to decode got: [%v] [%v]", m, err)
+	}
+
+	var s []int
+	if err := DecodeJSON([]byte("[1,2,3]"), &s); err != nil || !reflect.DeepEqual(s, []int{1, 2, 3}) {
+		t.Errorf("Expected slice to decode got: [%v] [%v]", s, err)
+	}
+
+	if err := DecodeJSON(b, value); err == nil {
+		t.Error("Expected error decoding into a non pointer")
+	}
+
+	if err := DecodeJSON([]byte("{} trailing"), &m); err == nil {
+		t.Error("Expected error decoding trailing data")
+	}
+}
+
+func TestShouldGetJSONZeroValueOnNoContent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	v := struct{ SomeProperty string }{SomeProperty: "stale"}
+	re, err := New().GetJSON(ts.URL, &v, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusNoContent)
+	if v.SomeProperty != "" {
+		t.Errorf("Expected zero value got: [%+v]", v)
+	}
+}
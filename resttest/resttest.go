@@ -0,0 +1,53 @@
+// Package resttest provides HTTP servers and helpers for testing code built on the rest client.
+package resttest
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// NewEchoServer starts a server answering every request with its body, decompressed when it was sent
+// gzipped and compressed again when the client accepts gzip. Callers must close it.
+func NewEchoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ReadRequestBody(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		WriteResponseBody(w, r, body)
+	}))
+}
+
+// ReadRequestBody reads the request body, decompressing it when it was sent gzipped.
+func ReadRequestBody(r *http.Request) ([]byte, error) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		body = zr
+	}
+	return ioutil.ReadAll(body)
+}
+
+// WriteResponseBody writes body with a 200 status, compressing it when the client accepts gzip.
+func WriteResponseBody(w http.ResponseWriter, r *http.Request, body []byte) {
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(http.StatusOK)
+	zw := gzip.NewWriter(w)
+	zw.Write(body)
+	zw.Close()
+}
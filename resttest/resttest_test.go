@@ -0,0 +1,57 @@
+package resttest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestShouldEchoRequestBody(t *testing.T) {
+	ts := NewEchoServer()
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader("{\"someProperty\":\"someValue\"}"))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, _ := ioutil.ReadAll(res.Body)
+	if string(body) != "{\"someProperty\":\"someValue\"}" {
+		t.Errorf("Expected body: [%v] got: [%v]", "{\"someProperty\":\"someValue\"}", string(body))
+	}
+}
+
+func TestShouldEchoGzipRequestBodyGzipped(t *testing.T) {
+	ts := NewEchoServer()
+	defer ts.Close()
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("payload"))
+	zw.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, &compressed)
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer res.Body.Close()
+
+	if encoding := res.Header.Get("Content-Encoding"); encoding != "gzip" {
+		t.Errorf("Expected Content-Encoding: [%v] got: [%v]", "gzip", encoding)
+	}
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	body, _ := ioutil.ReadAll(zr)
+	if string(body) != "payload" {
+		t.Errorf("Expected body: [%v] got: [%v]", "payload", string(body))
+	}
+}
@@ -0,0 +1,263 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+type retryPolicy struct {
+	maxAttempts       int
+	backoff           time.Duration
+	errorSubstrings   []string
+	minAttemptTimeout time.Duration
+	backoffCap        time.Duration
+	jitter            float64
+	statusCodes       map[int]bool
+}
+
+// WithRetry retries failed requests until maxAttempts attempts were made, waiting backoff between them.
+// Network errors are retried as long as the request body can be replayed, and failures writing the
+// request or reading a truncated response body are retried for idempotent methods. Replaying a body
+// needs a GetBody, which is set for *bytes.Buffer, *bytes.Reader and *strings.Reader bodies and can be
+// set by the request callback for any other body.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retry.maxAttempts = maxAttempts
+		c.retry.backoff = backoff
+	}
+}
+
+// WithRetryOnErrorContaining also retries transport errors whose message contains any of the given substrings,
+// for upstreams whose failures (e.g. "connection reset by peer", "EOF") only surface as strings.
+func WithRetryOnErrorContaining(substrings ...string) Option {
+	return func(c *Client) {
+		c.retry.errorSubstrings = append(append([]string(nil), c.retry.errorSubstrings...), substrings...)
+	}
+}
+
+// WithExponentialBackoff doubles the WithRetry backoff after every attempt, up to max.
+func WithExponentialBackoff(max time.Duration) Option {
+	return func(c *Client) {
+		c.retry.backoffCap = max
+	}
+}
+
+// WithRetryJitter shortens every backoff by a random part of up to fraction (0 to 1), so clients failing
+// together don't retry in lockstep.
+func WithRetryJitter(fraction float64) Option {
+	return func(c *Client) {
+		c.retry.jitter = fraction
+	}
+}
+
+// WithRetryOnStatus also retries responses with any of the given status codes, e.g. 502, 503 and 504, whatever
+// a custom WithErrorClassifier makes of them.
+func WithRetryOnStatus(statusCodes ...int) Option {
+	return func(c *Client) {
+		retryable := make(map[int]bool, len(c.retry.statusCodes)+len(statusCodes))
+		for code := range c.retry.statusCodes {
+			retryable[code] = true
+		}
+		for _, code := range statusCodes {
+			retryable[code] = true
+		}
+		c.retry.statusCodes = retryable
+	}
+}
+
+// WithMinAttemptTimeout guarantees every retry at least d before the request deadline, so backoff
+// can't leave the last attempt a uselessly short timeout. Retrying stops once less than d is left.
+func WithMinAttemptTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.retry.minAttemptTimeout = d
+	}
+}
+
+// backoffFor returns the backoff after the given attempt, starting at 1.
+func (p *retryPolicy) backoffFor(attempt int) time.Duration {
+	backoff := p.backoff
+	if p.backoffCap > 0 {
+		for i := 1; i < attempt && backoff < p.backoffCap; i++ {
+			backoff *= 2
+		}
+		if backoff > p.backoffCap {
+			backoff = p.backoffCap
+		}
+	}
+	if p.jitter > 0 {
+		backoff -= time.Duration(rand.Float64() * p.jitter * float64(backoff))
+	}
+	return backoff
+}
+
+func (p *retryPolicy) retryable(err error) bool {
+	if isContextError(err) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	msg := err.Error()
+	for _, substring := range p.errorSubstrings {
+		if strings.Contains(msg, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// isContextError reports whether err comes from the request context being canceled or past its deadline.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// classifyAttempt classifies an attempt of req. The status codes of WithRetryOnStatus are always retryable.
+// Without a custom classifier a failure reading the response body or writing the request is retryable for
+// idempotent methods only, as the server may have acted on it.
+func (c *Client) classifyAttempt(req *http.Request, re *ResponseEntity, err error) ErrorClass {
+	if err == nil && c.retry.statusCodes[re.StatusCode] {
+		return ErrorClassRetryable
+	}
+	if c.classifier == nil && (isBodyReadError(err) || isWriteError(err)) {
+		if idempotent(req.Method) {
+			return ErrorClassRetryable
+		}
+		return ErrorClassFatal
+	}
+	return c.classify(re, err)
+}
+
+func (c *Client) sendWithRetry(req *http.Request) (ResponseEntity, error) {
+	for attempt := 1; ; attempt++ {
+		re, err := c.send(req)
+		retryable := c.classifyAttempt(req, &re, err) == ErrorClassRetryable
+		if attempt >= c.retry.maxAttempts || !retryable {
+			return re, err
+		}
+
+		next, ok := rewindRequest(req)
+		if !ok {
+			return re, err
+		}
+
+		backoff, ok := backoffWithin(req.Context(), c.retry.backoffFor(attempt), c.retry.minAttemptTimeout)
+		if !ok {
+			return re, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return re, err
+		}
+		req = next
+	}
+}
+
+// backoffWithin returns backoff unchanged while another attempt fits before the ctx deadline, reporting
+// false to stop retrying when sleeping would reach the deadline or leave less than minAttempt of it.
+func backoffWithin(ctx context.Context, backoff, minAttempt time.Duration) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return backoff, true
+	}
+	remaining := time.Until(deadline)
+	if remaining <= backoff || remaining-backoff < minAttempt {
+		return 0, false
+	}
+	return backoff, true
+}
+
+// bodyReadError is a failure reading the response body after the response headers arrived.
+type bodyReadError struct {
+	err error
+}
+
+func (e *bodyReadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *bodyReadError) Unwrap() error {
+	return e.err
+}
+
+func isBodyReadError(err error) bool {
+	var readErr *bodyReadError
+	return errors.As(err, &readErr)
+}
+
+// writeError is a failure sending the request, e.g. a connection dropped mid-upload.
+type writeError struct {
+	err error
+}
+
+func (e *writeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *writeError) Unwrap() error {
+	return e.err
+}
+
+func isWriteError(err error) bool {
+	var wErr *writeError
+	return errors.As(err, &wErr)
+}
+
+// writeTrace records whether writing the request failed.
+type writeTrace struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (wt *writeTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			wt.mu.Lock()
+			defer wt.mu.Unlock()
+			wt.err = info.Err
+		},
+	}
+}
+
+func (wt *writeTrace) failed() bool {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	return wt.err != nil
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// rewindRequest returns a copy of req with a fresh body, if the body can be replayed.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	next.Body = body
+	return next, true
+}
@@ -0,0 +1,315 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetryOnErrorContaining(t *testing.T) {
+	ts, attempts := flakyTestServer(1)
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithRetryOnErrorContaining("EOF"))
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldNotRetryOnUnmatchedError(t *testing.T) {
+	ts, attempts := flakyTestServer(1)
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithRetryOnErrorContaining("connection reset by peer"))
+	if _, err := c.Get(ts.URL, JSONRequestCallback); err == nil {
+		t.Error("Expected error")
+	}
+
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+// flakyTestServer drops the connection of the first failures requests before answering.
+func flakyTestServer(failures int32) (*httptest.Server, *int32) {
+	attempts := new(int32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) <= failures {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	}))
+	return ts, attempts
+}
+
+func TestShouldRetryTruncatedBodyForIdempotentMethods(t *testing.T) {
+	ts, attempts := truncatingTestServer()
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond))
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), "{\"someProperty\":\"someValue\"}")
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 2, n)
+	}
+}
+
+func TestShouldNotRetryTruncatedBodyForPost(t *testing.T) {
+	ts, attempts := truncatingTestServer()
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond))
+	_, err := c.Post(ts.URL, strings.NewReader("{}"), JSONRequestCallback)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected error: [%v] got: [%v]", io.ErrUnexpectedEOF, err)
+	}
+
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+// truncatingTestServer closes the connection mid-body on the first request.
+func truncatingTestServer() (*httptest.Server, *int32) {
+	attempts := new(int32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) == 1 {
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n{\"some")
+				buf.Flush()
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	}))
+	return ts, attempts
+}
+
+func TestShouldStopRetryingWhenBackoffExceedsDeadline(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusServiceUnavailable)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	c := FromContext(ctx, WithRetry(5, time.Second), WithErrorClassifier(testClassifier))
+	start := time.Now()
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusServiceUnavailable)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected retries to stop immediately got: [%v]", elapsed)
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldStopRetryingWhenBackoffReachesDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if backoff, ok := backoffWithin(ctx, 100*time.Millisecond, 0); !ok || backoff != 100*time.Millisecond {
+		t.Errorf("Expected backoff within deadline got: [%v %v]", backoff, ok)
+	}
+
+	if _, ok := backoffWithin(ctx, 2*time.Second, 0); ok {
+		t.Error("Expected backoff past the deadline to stop retrying")
+	}
+
+	if backoff, ok := backoffWithin(context.Background(), time.Hour, 0); !ok || backoff != time.Hour {
+		t.Errorf("Expected backoff without deadline got: [%v %v]", backoff, ok)
+	}
+}
+
+func TestShouldKeepMinAttemptTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var remaining []time.Duration
+	c := FromContext(ctx,
+		WithRetry(10, 100*time.Millisecond),
+		WithMinAttemptTimeout(250*time.Millisecond),
+		WithErrorClassifier(func(re *ResponseEntity, err error) ErrorClass {
+			return ErrorClassRetryable
+		}),
+		WithDryRun(func(r *http.Request) {
+			deadline, _ := r.Context().Deadline()
+			remaining = append(remaining, time.Until(deadline))
+		}))
+
+	if _, err := c.Get("http://api.example.com/users", nil); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if len(remaining) < 2 || len(remaining) > 4 {
+		t.Errorf("Expected a few attempts got: [%v]", len(remaining))
+	}
+	for _, r := range remaining {
+		if r < 250*time.Millisecond {
+			t.Errorf("Expected attempt timeout of at least: [%v] got: [%v]", 250*time.Millisecond, r)
+		}
+	}
+}
+
+func TestShouldRetryFailedUploadForIdempotentMethods(t *testing.T) {
+	ts, attempts := droppingUploadTestServer()
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond))
+
+	payload := bytes.Repeat([]byte("0123456789"), 1<<20)
+	re, err := c.Put(ts.URL, bytes.NewReader(payload), nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), strconv.Itoa(len(payload)))
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 2, n)
+	}
+
+	atomic.StoreInt32(attempts, 0)
+	if _, err := c.Post(ts.URL, bytes.NewReader(payload), nil); err == nil {
+		t.Error("Expected POST upload failure not to be retried")
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldNotRetryFailedUploadClassifiedFatal(t *testing.T) {
+	ts, attempts := droppingUploadTestServer()
+	defer ts.Close()
+
+	fatal := func(re *ResponseEntity, err error) ErrorClass {
+		if err != nil {
+			return ErrorClassFatal
+		}
+		return ErrorClassNone
+	}
+	c := New(WithRetry(3, 10*time.Millisecond), WithErrorClassifier(fatal))
+
+	if _, err := c.Put(ts.URL, bytes.NewReader(bytes.Repeat([]byte("0123456789"), 1<<20)), nil); err == nil {
+		t.Error("Expected the upload failure classified fatal not to be retried")
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+func TestShouldNotRetryTruncatedBodyClassifiedFatal(t *testing.T) {
+	ts, attempts := truncatingTestServer()
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithErrorClassifier(func(re *ResponseEntity, err error) ErrorClass {
+		return ErrorClassFatal
+	}))
+	if _, err := c.Get(ts.URL, JSONRequestCallback); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected error: [%v] got: [%v]", io.ErrUnexpectedEOF, err)
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 1, n)
+	}
+}
+
+// droppingUploadTestServer drops the connection mid-upload on the first request.
+func droppingUploadTestServer() (*httptest.Server, *int32) {
+	attempts := new(int32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) == 1 {
+			io.CopyN(ioutil.Discard, r.Body, 1024)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		n, _ := io.Copy(ioutil.Discard, r.Body)
+		w.Write([]byte(strconv.FormatInt(n, 10)))
+	}))
+	return ts, attempts
+}
+
+func TestShouldBackoffExponentially(t *testing.T) {
+	p := retryPolicy{backoff: 10 * time.Millisecond, backoffCap: 50 * time.Millisecond}
+
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	for i, backoff := range expected {
+		if b := p.backoffFor(i + 1); b != backoff {
+			t.Errorf("Expected backoff after attempt %v: [%v] got: [%v]", i+1, backoff, b)
+		}
+	}
+}
+
+func TestShouldJitterBackoff(t *testing.T) {
+	p := retryPolicy{backoff: 100 * time.Millisecond, jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		if b := p.backoffFor(1); b < 50*time.Millisecond || b > 100*time.Millisecond {
+			t.Fatalf("Expected backoff within jitter got: [%v]", b)
+		}
+	}
+}
+
+func TestShouldRetryOnStatus(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithExponentialBackoff(time.Second), WithRetryOnStatus(http.StatusServiceUnavailable, http.StatusBadGateway))
+	re, err := c.Post(ts.URL, strings.NewReader("{}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 3, n)
+	}
+}
+
+func TestShouldRetryOnStatusWithCustomClassifier(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusServiceUnavailable, http.StatusOK)
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithRetryOnStatus(http.StatusServiceUnavailable), WithErrorClassifier(func(re *ResponseEntity, err error) ErrorClass {
+		return ErrorClassNone
+	}))
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 2, n)
+	}
+}
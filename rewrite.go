@@ -0,0 +1,17 @@
+package rest
+
+// WithURLRewriter transforms every target URL with fn before the request is built, e.g. for DNS overrides
+// or canary routing. fn gets URLs already resolved against the WithBaseURL base, and the rewritten URL is
+// the one parsed and sent; an error from fn fails the request.
+func WithURLRewriter(fn func(url string) (string, error)) Option {
+	return func(c *Client) {
+		c.urlRewriter = fn
+	}
+}
+
+func (c *Client) rewriteURL(url string) (string, error) {
+	if c.urlRewriter == nil {
+		return url, nil
+	}
+	return c.urlRewriter(url)
+}
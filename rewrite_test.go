@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestShouldRewriteURL(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	c := New(WithURLRewriter(func(url string) (string, error) {
+		return strings.Replace(url, "https://api.example.com", ts.URL, 1), nil
+	}))
+
+	re, err := c.Post("https://api.example.com/users", strings.NewReader("{\"name\":\"jose\"}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), "{\"name\":\"jose\"}")
+}
+
+func TestShouldFailOnURLRewriterError(t *testing.T) {
+	rewriteErr := errors.New("no route")
+	c := New(WithURLRewriter(func(url string) (string, error) {
+		return "", rewriteErr
+	}))
+
+	_, err := c.Get("https://api.example.com/users", JSONRequestCallback)
+	if !errors.Is(err, rewriteErr) {
+		t.Errorf("Expected error: [%v] got: [%v]", rewriteErr, err)
+	}
+}
@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"net/http"
+)
+
+type clientRoundTripper struct {
+	client *Client
+}
+
+// RoundTripper returns a http.RoundTripper that sends requests through this Client's exchange,
+// so code built on a standard http.Client gets the same behavior as the Client verb methods. Error
+// statuses are returned as responses, even with WithProblemDetails.
+func (c *Client) RoundTripper() http.RoundTripper {
+	return &clientRoundTripper{client: c}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *clientRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := rt.client.withContext(req.Context())
+	// a RoundTripper reports failures to get a response, never the status of one
+	c.problemErrors = false
+	re, err := c.Exchange(req.URL.String(), req.Method, req.Body, func(r *http.Request) {
+		for name, values := range req.Header {
+			r.Header[name] = append([]string(nil), values...)
+		}
+		r.Host = req.Host
+		// lets retries replay the body, unless the exchange already replaced it, e.g. compressing it
+		if r.GetBody == nil {
+			r.GetBody = req.GetBody
+		}
+		if r.ContentLength == 0 {
+			r.ContentLength = req.ContentLength
+		}
+	})
+	if err != nil {
+		// the exchange may fail before the transport gets to close the body, as RoundTrip must
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	res := re.ToHTTPResponse()
+	res.Request = req
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRoundTripThroughClient(t *testing.T) {
+	c := New()
+	ts := entityTestServer()
+	defer ts.Close()
+
+	hc := &http.Client{Transport: c.RoundTripper()}
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader("{\"someProperty\":\"someValue\"}"))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := hc.Do(req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer res.Body.Close()
+
+	assertStatusCode(t, res.StatusCode, http.StatusOK)
+	assertHeader(t, res.Header, "Content-Type", "application/json")
+
+	body, _ := ioutil.ReadAll(res.Body)
+	assertBody(t, string(body), "{\"someProperty\":\"someValue\"}")
+}
+
+func TestShouldRetryRoundTripsThroughClient(t *testing.T) {
+	ts, attempts := statusSequenceTestServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK)
+	defer ts.Close()
+
+	c := New(WithRetry(3, 10*time.Millisecond), WithRetryOnStatus(http.StatusServiceUnavailable))
+	hc := &http.Client{Transport: c.RoundTripper()}
+
+	res, err := hc.Post(ts.URL, "application/json", strings.NewReader("{\"someProperty\":\"someValue\"}"))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer res.Body.Close()
+
+	assertStatusCode(t, res.StatusCode, http.StatusOK)
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Errorf("Expected attempts: [%v] got: [%v]", 3, n)
+	}
+}
+
+func TestShouldReturnProblemStatusesFromRoundTrip(t *testing.T) {
+	ts := problemTestServer()
+	defer ts.Close()
+
+	hc := &http.Client{Transport: New(WithProblemDetails()).RoundTripper()}
+	res, err := hc.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer res.Body.Close()
+	assertStatusCode(t, res.StatusCode, http.StatusForbidden)
+}
+
+func TestShouldCloseRequestBodyWhenRoundTripFails(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("{}")}
+	req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:1", body)
+
+	rt := New(WithCAFile(filepath.Join(t.TempDir(), "ca.pem"))).RoundTripper()
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Error("Expected the CA file error")
+	}
+	if !body.closed {
+		t.Error("Expected the request body to be closed")
+	}
+}
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
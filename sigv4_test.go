@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The expected signatures are from the AWS Signature Version 4 test suite.
+var sigV4TestCredentials = AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+func TestShouldSignAWSV4(t *testing.T) {
+	cases := map[string]string{
+		"https://example.amazonaws.com/":                             "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		"https://example.amazonaws.com/?Param2=value2&Param1=value1": "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	for target, signature := range cases {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		if err := signAWSV4(req, sigV4TestCredentials, "us-east-1", "service", now); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+
+		expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + signature
+		assertHeader(t, req.Header, "Authorization", expected)
+	}
+}
+
+func TestShouldSignRequestsWithAWSV4Middleware(t *testing.T) {
+	var sent *http.Request
+	c := New(WithMiddleware(AWSSigV4Middleware(AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}, "eu-west-1", "s3")),
+		WithTransport(RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody}, nil
+		})))
+
+	if _, err := c.Put("https://bucket.s3.amazonaws.com/some%20key", strings.NewReader("payload"), nil); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, sent.Header, "X-Amz-Security-Token", "session")
+	assertHeader(t, sent.Header, "X-Amz-Content-Sha256", "239f59ed55e737c77147cf55ad0c1b030b6d7ee748a7426952f9b852d5a935e5")
+	if auth := sent.Header.Get("Authorization"); !strings.Contains(auth, "/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, ") {
+		t.Errorf("Expected signed S3 request got: [%v]", auth)
+	}
+}
@@ -0,0 +1,185 @@
+package rest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultEventRetry is how long Subscribe waits before reconnecting until the server sends a retry hint.
+const defaultEventRetry = 3 * time.Second
+
+// Event struct represents a Server-Sent Event.
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// eventStream holds the state kept across reconnections of a subscription.
+type eventStream struct {
+	lastEventID string
+	retry       time.Duration
+}
+
+// Subscribe consumes the text/event-stream at the given URL, calling handler for every event. When the
+// stream ends or the connection fails on the network it reconnects after the retry hint of the server,
+// sending the Last-Event-ID of the last event seen. It returns the handler's error, the error of the
+// Client's context once done, the error of a request that can't be sent, or an error for a response that
+// isn't an event stream. A 204 response ends the subscription.
+func (c *Client) Subscribe(url string, handler func(e Event) error, requestCallback func(r *http.Request)) error {
+	stream := &eventStream{retry: defaultEventRetry}
+	for {
+		err := c.subscribeOnce(url, stream, handler, requestCallback)
+		if err != errReconnect {
+			return err
+		}
+
+		select {
+		case <-c.context().Done():
+			return c.context().Err()
+		case <-time.After(stream.retry):
+		}
+	}
+}
+
+// errReconnect reports a stream that ended or failed and should be reconnected.
+var errReconnect = errors.New("rest: event stream reconnect")
+
+func (c *Client) subscribeOnce(url string, stream *eventStream, handler func(e Event) error, requestCallback func(r *http.Request)) error {
+	se, err := c.GetStream(url, func(r *http.Request) {
+		r.Header.Set("Accept", "text/event-stream")
+		r.Header.Set("Cache-Control", "no-cache")
+		if len(stream.lastEventID) > 0 {
+			r.Header.Set("Last-Event-ID", stream.lastEventID)
+		}
+		if requestCallback != nil {
+			requestCallback(r)
+		}
+	})
+	if err != nil {
+		if c.context().Err() != nil {
+			return c.context().Err()
+		}
+		if !reconnectable(err) {
+			return err
+		}
+		return errReconnect
+	}
+	defer se.Body.Close()
+
+	if se.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if se.StatusCode != http.StatusOK {
+		return fmt.Errorf("rest: event stream from %s failed with status %d", url, se.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(se.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		return fmt.Errorf("rest: event stream from %s has Content-Type %q", url, se.Header.Get("Content-Type"))
+	}
+
+	if err := stream.read(se.Body, handler); err != nil {
+		return err
+	}
+	if c.context().Err() != nil {
+		return c.context().Err()
+	}
+	return errReconnect
+}
+
+// reconnectable reports whether a failed connection attempt is worth retrying, that is whether it failed
+// on the network rather than on an invalid request or the Client's configuration.
+func reconnectable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// read dispatches the events read from r to handler, returning nil once r ends or fails and the
+// handler's error if it returns one.
+func (s *eventStream) read(r io.Reader, handler func(e Event) error) error {
+	lr := &eventLineReader{br: bufio.NewReader(r)}
+	var data strings.Builder
+	var event string
+	for {
+		line, err := lr.readLine()
+		if err != nil {
+			// an incomplete event at the end of the stream is discarded
+			return nil
+		}
+
+		if len(line) == 0 {
+			if data.Len() > 0 {
+				if len(event) == 0 {
+					event = "message"
+				}
+				e := Event{ID: s.lastEventID, Event: event, Data: strings.TrimSuffix(data.String(), "\n")}
+				if err := handler(e); err != nil {
+					return err
+				}
+			}
+			data.Reset()
+			event = ""
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "event":
+			event = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// eventLineReader splits an event stream into lines ended by CRLF, LF or a lone CR.
+type eventLineReader struct {
+	br     *bufio.Reader
+	skipLF bool
+}
+
+// readLine returns the next line without its terminator. A CR ends the line right away, without waiting
+// for the next byte, and a LF right after it is skipped on the next call.
+func (lr *eventLineReader) readLine() (string, error) {
+	var line strings.Builder
+	for {
+		b, err := lr.br.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		skipLF := lr.skipLF
+		lr.skipLF = false
+		switch {
+		case b == '\n' && skipLF:
+			continue
+		case b == '\n':
+			return line.String(), nil
+		case b == '\r':
+			lr.skipLF = true
+			return line.String(), nil
+		}
+		line.WriteByte(b)
+	}
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShouldSubscribeAndReconnect(t *testing.T) {
+	var connections int32
+	var lastEventIDs []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		if atomic.AddInt32(&connections, 1) == 1 {
+			w.Write([]byte("retry: 10\n: keep alive\n\nid: 1\ndata: first\n\nevent: update\nid: 2\ndata: line one\ndata:line two\r\n\r\ndata: incomplete"))
+			return
+		}
+		w.Write([]byte("event: done\ndata: bye\n\n"))
+	}))
+	defer ts.Close()
+
+	errDone := errors.New("done")
+	var events []Event
+	err := New().Subscribe(ts.URL, func(e Event) error {
+		events = append(events, e)
+		if e.Event == "done" {
+			return errDone
+		}
+		return nil
+	}, nil)
+	if err != errDone {
+		t.Errorf("Expected error: [%v] got: [%v]", errDone, err)
+	}
+
+	expected := []Event{
+		{ID: "1", Event: "message", Data: "first"},
+		{ID: "2", Event: "update", Data: "line one\nline two"},
+		{ID: "2", Event: "done", Data: "bye"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected events: [%v] got: [%v]", expected, events)
+	}
+	if !reflect.DeepEqual(lastEventIDs, []string{"", "2"}) {
+		t.Errorf("Expected Last-Event-ID headers: [%v] got: [%v]", []string{"", "2"}, lastEventIDs)
+	}
+}
+
+func TestShouldSplitEventsOnCarriageReturns(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: first\r\rdata: second\r\n\r\nevent: done\ndata: bye\n\n"))
+	}))
+	defer ts.Close()
+
+	errDone := errors.New("done")
+	var events []Event
+	New().Subscribe(ts.URL, func(e Event) error {
+		events = append(events, e)
+		if e.Event == "done" {
+			return errDone
+		}
+		return nil
+	}, nil)
+
+	expected := []Event{
+		{Event: "message", Data: "first"},
+		{Event: "message", Data: "second"},
+		{Event: "done", Data: "bye"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected events: [%v] got: [%v]", expected, events)
+	}
+}
+
+func TestShouldNotReconnectInvalidSubscriptions(t *testing.T) {
+	err := New().Subscribe("http://127.0.0.1:1/%zz", func(e Event) error { return nil }, nil)
+	if err == nil || err == errReconnect {
+		t.Errorf("Expected the request error got: [%v]", err)
+	}
+}
+
+func TestShouldStopSubscriptionOnNoContent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	if err := New().Subscribe(ts.URL, func(e Event) error { return nil }, nil); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestShouldFailSubscriptionToOtherContent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	if err := New().Subscribe(ts.URL, func(e Event) error { return nil }, nil); err == nil {
+		t.Error("Expected an error for a response that isn't an event stream")
+	}
+}
+
+func TestShouldEndSubscriptionWithContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: tick\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := FromContext(ctx).Subscribe(ts.URL, func(e Event) error {
+		cancel()
+		return nil
+	}, nil)
+	if err != context.Canceled {
+		t.Errorf("Expected error: [%v] got: [%v]", context.Canceled, err)
+	}
+}
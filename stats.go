@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram kept by Stats.
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyBucket struct represents the number of requests that took at most UpperBound, and more than
+// the previous bucket's bound. The last bucket has no upper bound and reports math.MaxInt64.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      int64
+}
+
+// ClientStats struct represents a snapshot of the requests made by a Client.
+type ClientStats struct {
+	Requests       int64
+	Errors         int64
+	StatusCodes    map[int]int64
+	AverageLatency time.Duration
+	LatencyBuckets []LatencyBucket
+}
+
+type clientStats struct {
+	mu           sync.Mutex
+	requests     int64
+	errors       int64
+	statusCodes  map[int]int64
+	totalLatency time.Duration
+	buckets      []LatencyBucket
+}
+
+func newClientStats(bounds []time.Duration) *clientStats {
+	sorted := append([]time.Duration(nil), bounds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	buckets := make([]LatencyBucket, 0, len(sorted)+1)
+	for _, bound := range sorted {
+		buckets = append(buckets, LatencyBucket{UpperBound: bound})
+	}
+	buckets = append(buckets, LatencyBucket{UpperBound: math.MaxInt64})
+	return &clientStats{statusCodes: make(map[int]int64), buckets: buckets}
+}
+
+// WithLatencyBuckets replaces the upper bounds of the latency histogram reported by Stats.
+func WithLatencyBuckets(bounds ...time.Duration) Option {
+	return func(c *Client) {
+		c.stats = newClientStats(bounds)
+	}
+}
+
+func (s *clientStats) record(statusCode int, latency time.Duration, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	s.totalLatency += latency
+	if err != nil {
+		s.errors++
+	}
+	if statusCode != 0 {
+		s.statusCodes[statusCode]++
+	}
+	for i := range s.buckets {
+		if latency <= s.buckets[i].UpperBound {
+			s.buckets[i].Count++
+			break
+		}
+	}
+}
+
+// Stats returns the number of requests made so far, broken down by status code, along with the number
+// of failed requests and their latency, on average and as a histogram. Clients derived through With
+// share their stats.
+func (c *Client) Stats() ClientStats {
+	stats := ClientStats{StatusCodes: make(map[int]int64)}
+	if c.stats == nil {
+		return stats
+	}
+
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	stats.Requests = c.stats.requests
+	stats.Errors = c.stats.errors
+	for statusCode, count := range c.stats.statusCodes {
+		stats.StatusCodes[statusCode] = count
+	}
+	stats.LatencyBuckets = append([]LatencyBucket(nil), c.stats.buckets...)
+	if c.stats.requests > 0 {
+		stats.AverageLatency = c.stats.totalLatency / time.Duration(c.stats.requests)
+	}
+	return stats
+}
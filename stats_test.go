@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShouldTrackStats(t *testing.T) {
+	ts, _ := statusSequenceTestServer(http.StatusOK, http.StatusOK, http.StatusNotFound)
+	defer ts.Close()
+
+	c := New()
+	for i := 0; i < 3; i++ {
+		c.Get(ts.URL, JSONRequestCallback)
+	}
+	c.With(WithOperationName("broken")).Get("http://127.0.0.1:0", JSONRequestCallback)
+
+	stats := c.Stats()
+	if stats.Requests != 4 || stats.Errors != 1 {
+		t.Errorf("Expected 4 requests and 1 error got: [%+v]", stats)
+	}
+
+	if stats.StatusCodes[http.StatusOK] != 2 || stats.StatusCodes[http.StatusNotFound] != 1 {
+		t.Errorf("Expected per status counts got: [%v]", stats.StatusCodes)
+	}
+
+	if stats.AverageLatency <= 0 {
+		t.Errorf("Expected average latency got: [%v]", stats.AverageLatency)
+	}
+}
+
+func TestShouldBucketLatencies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay, _ := time.ParseDuration(r.URL.Query().Get("delay"))
+		time.Sleep(delay)
+	}))
+	defer ts.Close()
+
+	c := New(WithLatencyBuckets(200*time.Millisecond, 50*time.Millisecond))
+	for _, delay := range []string{"0s", "1ms", "100ms", "300ms"} {
+		c.Get(ts.URL+"?delay="+delay, nil)
+	}
+
+	buckets := c.Stats().LatencyBuckets
+	expected := []LatencyBucket{
+		{UpperBound: 50 * time.Millisecond, Count: 2},
+		{UpperBound: 200 * time.Millisecond, Count: 1},
+		{UpperBound: math.MaxInt64, Count: 1},
+	}
+	if !reflect.DeepEqual(buckets, expected) {
+		t.Errorf("Expected buckets: [%v] got: [%v]", expected, buckets)
+	}
+
+	if len(New().Stats().LatencyBuckets) != len(DefaultLatencyBuckets)+1 {
+		t.Errorf("Expected default buckets got: [%v]", New().Stats().LatencyBuckets)
+	}
+}
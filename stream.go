@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (rc *cancelReadCloser) Close() error {
+	err := rc.ReadCloser.Close()
+	rc.cancel()
+	return err
+}
+
+// StreamEntity struct represents a HTTP response whose body is read as it arrives.
+type StreamEntity struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
+}
+
+// ExchangeStream exchanges like Exchange but hands back the live response body instead of buffering it.
+// Gzip encoded bodies are decompressed on the fly while reading. As with ExchangeDuplex the client
+// timeout isn't applied, and callers must close the body.
+func (c *Client) ExchangeStream(url, method string, body io.Reader, requestCallback func(r *http.Request)) (StreamEntity, error) {
+	res, cancel, err := c.stream(url, method, body, requestCallback)
+	if err != nil {
+		return StreamEntity{Header: make(http.Header)}, err
+	}
+
+	rc := io.ReadCloser(res.Body)
+	if strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		rc = &gzipReadCloser{body: res.Body}
+		res.Header.Del("Content-Encoding")
+		res.Header.Del("Content-Length")
+	}
+	return StreamEntity{StatusCode: res.StatusCode, Header: res.Header, Body: &cancelReadCloser{ReadCloser: rc, cancel: cancel}}, nil
+}
+
+// GetStream gets the content from the given URL as a stream, see ExchangeStream. Callers must close the body.
+func (c *Client) GetStream(url string, requestCallback func(r *http.Request)) (StreamEntity, error) {
+	return c.ExchangeStream(url, http.MethodGet, nil, requestCallback)
+}
+
+// ExchangeDuplex sends reqBody while streaming back the response body, so both sides can be read and
+// written concurrently where the server supports it. The client timeout isn't applied as the exchange
+// lasts as long as the streams do; bind the Client to a context to bound it. Request bodies sent to hosts
+// set with WithGzipRequestForHosts are compressed up front, so they mustn't wait on the response. Callers
+// must close the returned body.
+func (c *Client) ExchangeDuplex(url, method string, reqBody io.Reader, requestCallback func(r *http.Request)) (io.ReadCloser, error) {
+	res, cancel, err := c.stream(url, method, reqBody, requestCallback)
+	if err != nil {
+		return nil, err
+	}
+	return &cancelReadCloser{ReadCloser: res.Body, cancel: cancel}, nil
+}
+
+// stream sends the request and returns the response with its body left unread, along with the
+// function cancelling the request once the body is done with.
+func (c *Client) stream(url, method string, body io.Reader, requestCallback func(r *http.Request)) (*http.Response, context.CancelFunc, error) {
+	req, err := c.newRequest(url, method, body)
+	if err != nil {
+		return nil, nil, wrapRequestError(method, url, err)
+	}
+
+	ctx, cancel := context.WithCancel(c.context())
+	req = req.WithContext(ctx)
+
+	if err := c.prepareRequest(req, requestCallback); err != nil {
+		cancel()
+		return nil, nil, wrapRequestError(method, url, err)
+	}
+
+	if c.dryRun != nil {
+		c.dryRun(req)
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody}, cancel, nil
+	}
+
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		cancel()
+		return nil, nil, wrapRequestError(method, url, err)
+	}
+	return res, cancel, nil
+}
+
+// gzipReadCloser decompresses body lazily, on its first read.
+type gzipReadCloser struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (rc *gzipReadCloser) Read(p []byte) (int, error) {
+	if rc.zr == nil && rc.err == nil {
+		rc.zr, rc.err = gzip.NewReader(rc.body)
+	}
+	if rc.err != nil {
+		return 0, rc.err
+	}
+	return rc.zr.Read(p)
+}
+
+func (rc *gzipReadCloser) Close() error {
+	return rc.body.Close()
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldExchangeDuplex(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		rc.EnableFullDuplex()
+		w.WriteHeader(http.StatusOK)
+		rc.Flush()
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			fmt.Fprintf(w, "echo %s\n", scanner.Text())
+			rc.Flush()
+		}
+	}))
+	defer ts.Close()
+
+	pr, pw := io.Pipe()
+	c := New()
+	body, err := c.ExchangeDuplex(ts.URL, http.MethodPost, pr, nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer body.Close()
+
+	lines := bufio.NewReader(body)
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(pw, "ping %d\n", i)
+		line, err := lines.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		assertBody(t, line, fmt.Sprintf("echo ping %d\n", i))
+	}
+	pw.Close()
+
+	if rest, _ := ioutil.ReadAll(lines); len(rest) != 0 {
+		t.Errorf("Expected end of stream got: [%v]", string(rest))
+	}
+}
+
+func TestShouldDecompressStreamOnTheFly(t *testing.T) {
+	const lines = 50000
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		for i := 0; i < lines; i++ {
+			fmt.Fprintf(zw, "{\"line\":%d}\n", i)
+		}
+		zw.Close()
+	}))
+	defer ts.Close()
+
+	c := New()
+	se, err := c.ExchangeStream(ts.URL, http.MethodGet, nil, func(r *http.Request) {
+		r.Header.Set("Accept-Encoding", "gzip")
+	})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer se.Body.Close()
+
+	assertStatusCode(t, se.StatusCode, http.StatusOK)
+	assertHeader(t, se.Header, "Content-Encoding", "")
+
+	scanner := bufio.NewScanner(se.Body)
+	count := 0
+	for scanner.Scan() {
+		if expected := fmt.Sprintf("{\"line\":%d}", count); scanner.Text() != expected {
+			t.Fatalf("Expected line: [%v] got: [%v]", expected, scanner.Text())
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	if count != lines {
+		t.Errorf("Expected lines: [%v] got: [%v]", lines, count)
+	}
+}
+
+func TestShouldGetStreamWithoutBuffering(t *testing.T) {
+	const size = 64 * megabyte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		io.CopyN(w, zeroReader{}, size)
+	}))
+	defer ts.Close()
+
+	se, err := New().GetStream(ts.URL, nil)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer se.Body.Close()
+
+	assertStatusCode(t, se.StatusCode, http.StatusOK)
+	assertHeader(t, se.Header, "Content-Type", "application/octet-stream")
+	n, err := io.CopyBuffer(ioutil.Discard, se.Body, make([]byte, 32*1024))
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if n != size {
+		t.Errorf("Expected bytes: [%v] got: [%v]", size, n)
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+)
+
+const megabyte = 1 << 20
+
+type sizeBasedTimeout struct {
+	base  time.Duration
+	perMB time.Duration
+}
+
+// WithTimeout replaces the default 10s timeout of each request.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithDialTimeout replaces the default 5s timeout for establishing connections.
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.dialTimeout = d
+	}
+}
+
+// WithTLSHandshakeTimeout replaces the default 5s timeout for TLS handshakes.
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.tlsHandshakeTimeout = d
+	}
+}
+
+// WithSizeBasedTimeout scales the timeout of each request with its body size: base plus perMB for
+// every megabyte. Bodies of unknown length get the base timeout.
+func WithSizeBasedTimeout(base time.Duration, perMB time.Duration) Option {
+	return func(c *Client) {
+		c.sizeTimeout = &sizeBasedTimeout{base: base, perMB: perMB}
+	}
+}
+
+// WithOptionsTimeout sets a dedicated timeout for OPTIONS requests, so preflight style calls against
+// slow gateways fail fast without shortening other requests.
+func WithOptionsTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.optionsTimeout = d
+	}
+}
+
+// requestTimeout returns the timeout for the whole exchange of req.
+func (c *Client) requestTimeout(req *http.Request) time.Duration {
+	if req.Method == http.MethodOptions && c.optionsTimeout > 0 {
+		return c.optionsTimeout
+	}
+	if c.sizeTimeout == nil {
+		return c.Timeout()
+	}
+
+	timeout := c.sizeTimeout.base
+	if req.ContentLength > 0 {
+		timeout += time.Duration(float64(c.sizeTimeout.perMB) * float64(req.ContentLength) / megabyte)
+	}
+	return timeout
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShouldScaleTimeoutWithBodySize(t *testing.T) {
+	c := New(WithSizeBasedTimeout(time.Second, 2*time.Second))
+
+	cases := map[int]time.Duration{
+		0:             time.Second,
+		megabyte / 2:  2 * time.Second,
+		megabyte:      3 * time.Second,
+		10 * megabyte: 21 * time.Second,
+	}
+	for size, expected := range cases {
+		req, _ := http.NewRequest(http.MethodPut, "http://localhost", bytes.NewReader(make([]byte, size)))
+		if timeout := c.requestTimeout(req); timeout != expected {
+			t.Errorf("Expected timeout for %v bytes: [%v] got: [%v]", size, expected, timeout)
+		}
+	}
+}
+
+func TestShouldUseDefaultTimeoutWithoutSizeBasedTimeout(t *testing.T) {
+	c := New()
+	req, _ := http.NewRequest(http.MethodPut, "http://localhost", bytes.NewReader(make([]byte, megabyte)))
+	if timeout := c.requestTimeout(req); timeout != c.Timeout() {
+		t.Errorf("Expected timeout: [%v] got: [%v]", c.Timeout(), timeout)
+	}
+}
+
+func TestShouldApplyOptionsTimeout(t *testing.T) {
+	c := New(WithOptionsTimeout(100 * time.Millisecond))
+
+	options, _ := http.NewRequest(http.MethodOptions, "http://localhost", nil)
+	if timeout := c.requestTimeout(options); timeout != 100*time.Millisecond {
+		t.Errorf("Expected timeout: [%v] got: [%v]", 100*time.Millisecond, timeout)
+	}
+
+	get, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	if timeout := c.requestTimeout(get); timeout != c.Timeout() {
+		t.Errorf("Expected timeout: [%v] got: [%v]", c.Timeout(), timeout)
+	}
+
+	ts := testServer()
+	defer ts.Close()
+
+	start := time.Now()
+	if _, err := c.OptionsForAllow(ts.URL, JSONRequestCallback); err == nil {
+		t.Error("Expected OPTIONS to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected OPTIONS to fail fast got: [%v]", elapsed)
+	}
+}
+
+func TestShouldApplyTimeout(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	c := New(WithTimeout(100 * time.Millisecond))
+	if timeout := c.Timeout(); timeout != 100*time.Millisecond {
+		t.Errorf("Expected timeout: [%v] got: [%v]", 100*time.Millisecond, timeout)
+	}
+
+	_, err := c.Get(ts.URL, JSONRequestCallback)
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || !reqErr.Timeout() {
+		t.Errorf("Expected timeout error got: [%v]", err)
+	}
+}
+
+func TestShouldApplyTransportTimeouts(t *testing.T) {
+	c := New(WithTLSHandshakeTimeout(time.Second), WithDialTimeout(2*time.Second))
+
+	transport := c.roundTripper().(*http.Transport)
+	if transport.TLSHandshakeTimeout != time.Second {
+		t.Errorf("Expected TLS handshake timeout: [%v] got: [%v]", time.Second, transport.TLSHandshakeTimeout)
+	}
+
+	transport = New().roundTripper().(*http.Transport)
+	if transport.TLSHandshakeTimeout != New().TransportTimeout() {
+		t.Errorf("Expected TLS handshake timeout: [%v] got: [%v]", New().TransportTimeout(), transport.TLSHandshakeTimeout)
+	}
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing struct represents the latency breakdown of a HTTP exchange.
+type Timing struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+	BodyRead     time.Duration
+	Total        time.Duration
+}
+
+// timingTrace collects the Timing of an exchange. The trace hooks are guarded by mu as dials can run
+// concurrently, e.g. for Happy Eyeballs, and finish after the exchange returned.
+type timingTrace struct {
+	mu            sync.Mutex
+	timing        Timing
+	start         time.Time
+	dnsStart      time.Time
+	connectStarts map[string]time.Time
+	tlsStart      time.Time
+	readStart     time.Time
+}
+
+func newTimingTrace() *timingTrace {
+	return &timingTrace{start: time.Now(), connectStarts: make(map[string]time.Time)}
+}
+
+func (tt *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.timing.DNS = time.Since(tt.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.connectStarts[network+" "+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			// only the first successful dial is timed, the connection racing dials lost to isn't used
+			if start, ok := tt.connectStarts[network+" "+addr]; ok && err == nil && tt.timing.Connect == 0 {
+				tt.timing.Connect = time.Since(start)
+			}
+		},
+		TLSHandshakeStart: func() {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.timing.TLSHandshake = time.Since(tt.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.timing.FirstByte = time.Since(tt.start)
+		},
+	}
+}
+
+func (tt *timingTrace) bodyReadStarted() {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.readStart = time.Now()
+}
+
+func (tt *timingTrace) done() Timing {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	if !tt.readStart.IsZero() {
+		tt.timing.BodyRead = time.Since(tt.readStart)
+	}
+	tt.timing.Total = time.Since(tt.start)
+	return tt.timing
+}
@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShouldPopulateTiming(t *testing.T) {
+	c := New()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	}))
+	defer ts.Close()
+
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	timing := re.Timing
+	if timing.Connect <= 0 {
+		t.Errorf("Expected connect phase got: [%v]", timing.Connect)
+	}
+
+	if timing.FirstByte < 20*time.Millisecond || timing.FirstByte < timing.Connect {
+		t.Errorf("Expected first byte after connect and server delay got: [%v]", timing.FirstByte)
+	}
+
+	if timing.Total < timing.FirstByte || timing.Total < timing.BodyRead {
+		t.Errorf("Expected total to cover all phases got: [%v]", timing.Total)
+	}
+}
+
+func TestShouldTimeFirstSuccessfulOfConcurrentDials(t *testing.T) {
+	tt := newTimingTrace()
+	trace := tt.clientTrace()
+
+	var wg sync.WaitGroup
+	for _, addr := range []string{"[::1]:80", "127.0.0.1:80"} {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			trace.ConnectStart("tcp", addr)
+			var err error
+			if addr == "[::1]:80" {
+				err = errors.New("connection refused")
+			}
+			trace.ConnectDone("tcp", addr, err)
+		}(addr)
+	}
+	wg.Wait()
+
+	connect := tt.done().Connect
+	if connect <= 0 {
+		t.Errorf("Expected connect phase got: [%v]", connect)
+	}
+
+	trace.ConnectStart("tcp", "10.0.0.1:80")
+	time.Sleep(10 * time.Millisecond)
+	trace.ConnectDone("tcp", "10.0.0.1:80", nil)
+	if timing := tt.done(); timing.Connect != connect {
+		t.Errorf("Expected a later dial to keep connect phase: [%v] got: [%v]", connect, timing.Connect)
+	}
+}
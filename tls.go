@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ErrCertificatePinMismatch fails requests to servers presenting none of the pinned certificates.
+var ErrCertificatePinMismatch = errors.New("rest: no pinned certificate in the server chain")
+
+// WithTLSConfig uses a copy of config for TLS connections of the built transport, e.g. to present client
+// certificates for mutual TLS. The other TLS options apply on top of it.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = config.Clone()
+	}
+}
+
+// WithClientCertificate presents the PEM encoded certificate and key of the given files for mutual TLS.
+// A failure loading them fails every request with the loading error.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *Client) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.tlsErr = err
+			return
+		}
+		c.updateTLSConfig(func(config *tls.Config) {
+			config.Certificates = append(config.Certificates, cert)
+		})
+	}
+}
+
+// WithRootCAs verifies server certificates against pool instead of the system roots, e.g. for services
+// signed by an internal CA.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		c.updateTLSConfig(func(config *tls.Config) {
+			config.RootCAs = pool
+		})
+	}
+}
+
+// WithCAFile verifies server certificates against the PEM encoded certificates of the given file instead
+// of the system roots. A failure loading them fails every request with the loading error.
+func WithCAFile(path string) Option {
+	return func(c *Client) {
+		pool := x509.NewCertPool()
+		pem, err := ioutil.ReadFile(path)
+		if err == nil && !pool.AppendCertsFromPEM(pem) {
+			err = fmt.Errorf("rest: no PEM certificates in %s", path)
+		}
+		if err != nil {
+			c.tlsErr = err
+		}
+		c.updateTLSConfig(func(config *tls.Config) {
+			config.RootCAs = pool
+		})
+	}
+}
+
+// WithInsecureTLS disables the verification of server certificates and host names, leaving connections open
+// to man-in-the-middle attacks. It's only meant for development against self-signed staging endpoints.
+func WithInsecureTLS() Option {
+	return func(c *Client) {
+		c.updateTLSConfig(func(config *tls.Config) {
+			config.InsecureSkipVerify = true
+		})
+	}
+}
+
+// WithPinnedCertificates only accepts servers whose verified certificate chain holds a certificate with one of
+// the given pins: the base64 SHA-256 of either its subject public key info (as SPKIPin returns, with or without
+// the "sha256/" prefix) or of the whole certificate. Other servers fail with ErrCertificatePinMismatch. With
+// WithInsecureTLS there's no verified chain and only the server's own certificate is checked.
+func WithPinnedCertificates(pins ...string) Option {
+	pinned := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		pinned[strings.TrimPrefix(pin, "sha256/")] = true
+	}
+	matches := func(cert *x509.Certificate) bool {
+		certHash := sha256.Sum256(cert.Raw)
+		return pinned[base64.StdEncoding.EncodeToString(certHash[:])] || pinned[strings.TrimPrefix(SPKIPin(cert), "sha256/")]
+	}
+	return func(c *Client) {
+		c.updateTLSConfig(func(config *tls.Config) {
+			config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+				// the chain sent by the server may hold any certificate, only the verified chains are checked
+				for _, chain := range verifiedChains {
+					for _, cert := range chain {
+						if matches(cert) {
+							return nil
+						}
+					}
+				}
+				if len(verifiedChains) == 0 && len(rawCerts) > 0 {
+					if cert, err := x509.ParseCertificate(rawCerts[0]); err == nil && matches(cert) {
+						return nil
+					}
+				}
+				return ErrCertificatePinMismatch
+			}
+		})
+	}
+}
+
+// SPKIPin returns the pin of the subject public key info of cert, as "sha256/" and its base64 SHA-256.
+func SPKIPin(cert *x509.Certificate) string {
+	spkiHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(spkiHash[:])
+}
+
+// updateTLSConfig applies fn to a copy of the TLS config, so clients derived through With don't affect each other.
+func (c *Client) updateTLSConfig(fn func(config *tls.Config)) {
+	config := c.tlsConfig.Clone()
+	if config == nil {
+		config = &tls.Config{}
+	}
+	fn(config)
+	c.tlsConfig = config
+}
@@ -0,0 +1,201 @@
+package rest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShouldPresentClientCertificate(t *testing.T) {
+	ts := mutualTLSTestServer()
+	defer ts.Close()
+
+	certFile, keyFile := writeTestCertificate(t, "rest-client")
+	c := New(withTestServerTLS(ts), WithClientCertificate(certFile, keyFile))
+
+	re, err := c.Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "rest-client")
+}
+
+func TestShouldFailRequestsWithMissingClientCertificate(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	c := New(withTestServerTLS(ts), WithClientCertificate(certFile, filepath.Join(filepath.Dir(certFile), "key.pem")))
+
+	// the server doesn't ask for a certificate, so only an early failure reports the missing one
+	if _, err := c.Get(ts.URL, JSONRequestCallback); err == nil || !strings.Contains(err.Error(), certFile) {
+		t.Errorf("Expected error loading: [%v] got: [%v]", certFile, err)
+	}
+}
+
+func TestShouldNotShareTLSConfigWithDerivedClients(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, "rest-client")
+	c := New(WithTLSConfig(&tls.Config{ServerName: "api.example.com"}))
+	derived := c.With(WithClientCertificate(certFile, keyFile))
+
+	if len(c.tlsConfig.Certificates) != 0 || len(derived.tlsConfig.Certificates) != 1 {
+		t.Errorf("Expected certificate on derived client only got: [%v] [%v]", len(c.tlsConfig.Certificates), len(derived.tlsConfig.Certificates))
+	}
+	if derived.tlsConfig.ServerName != "api.example.com" {
+		t.Errorf("Expected server name: [%v] got: [%v]", "api.example.com", derived.tlsConfig.ServerName)
+	}
+}
+
+// mutualTLSTestServer requires a client certificate, answering with its common name.
+func mutualTLSTestServer() *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.StartTLS()
+	return ts
+}
+
+// writeTestCertificate writes a self-signed certificate and its key as PEM files in a temporary directory.
+func writeTestCertificate(t *testing.T, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return certFile, keyFile
+}
+
+func TestShouldTrustRootCAs(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	if _, err := New().Get(ts.URL, JSONRequestCallback); err == nil {
+		t.Error("Expected error for an unknown CA")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	if _, err := New(WithRootCAs(pool)).Get(ts.URL, JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600)
+	if _, err := New(WithCAFile(caFile)).Get(ts.URL, JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestShouldFailHandshakeWithMissingCAFile(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	c := New(WithCAFile(caFile))
+	_, err := c.Get(ts.URL, JSONRequestCallback)
+	if err == nil || !strings.Contains(err.Error(), caFile) {
+		t.Errorf("Expected error loading: [%v] got: [%v]", caFile, err)
+	}
+	if c.tlsConfig.InsecureSkipVerify {
+		t.Error("Certificate verification should stay on")
+	}
+
+	_, err = c.With(WithTLSConfig(&tls.Config{})).GetStream(ts.URL, nil)
+	if err == nil || !strings.Contains(err.Error(), caFile) {
+		t.Errorf("Expected error loading: [%v] got: [%v]", caFile, err)
+	}
+}
+
+func TestShouldAcceptPinnedCertificate(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	certHash := sha256.Sum256(ts.Certificate().Raw)
+	pins := []string{SPKIPin(ts.Certificate()), base64.StdEncoding.EncodeToString(certHash[:])}
+	for _, pin := range pins {
+		if _, err := New(withTestServerTLS(ts), WithPinnedCertificates("sha256/AAAA", pin)).Get(ts.URL, JSONRequestCallback); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	}
+}
+
+func TestShouldRejectUnpinnedCertificate(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	_, err := New(withTestServerTLS(ts), WithPinnedCertificates("sha256/AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")).Get(ts.URL, JSONRequestCallback)
+	if !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrCertificatePinMismatch, err)
+	}
+}
+
+func TestShouldRejectPinnedCertificateOutsideVerifiedChain(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// the server appends the pinned certificate to its chain without it signing anything in it
+	certFile, _ := writeTestCertificate(t, "pinned")
+	certPEM, _ := ioutil.ReadFile(certFile)
+	block, _ := pem.Decode(certPEM)
+	pinnedCert, _ := x509.ParseCertificate(block.Bytes)
+	ts.TLS.Certificates[0].Certificate = append(ts.TLS.Certificates[0].Certificate, block.Bytes)
+
+	_, err := New(withTestServerTLS(ts), WithPinnedCertificates(SPKIPin(pinnedCert))).Get(ts.URL, JSONRequestCallback)
+	if !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrCertificatePinMismatch, err)
+	}
+
+	_, err = New(WithInsecureTLS(), WithPinnedCertificates(SPKIPin(pinnedCert))).Get(ts.URL, JSONRequestCallback)
+	if !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("Expected error: [%v] got: [%v]", ErrCertificatePinMismatch, err)
+	}
+	if _, err := New(WithInsecureTLS(), WithPinnedCertificates(SPKIPin(ts.Certificate()))).Get(ts.URL, JSONRequestCallback); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+}
+
+func TestShouldSkipVerifyWithInsecureTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	re, err := New(WithInsecureTLS()).Get(ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+type traceContextKey struct{}
+
+type traceContext struct {
+	traceparent string
+	tracestate  string
+}
+
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// ContextWithTraceContext returns a copy of ctx carrying the W3C traceparent and tracestate values,
+// for instrumentation to hand the current span to the client.
+func ContextWithTraceContext(ctx context.Context, traceparent, tracestate string) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, traceContext{traceparent: traceparent, tracestate: tracestate})
+}
+
+// TraceContextFromContext returns the traceparent and tracestate values carried by ctx.
+func TraceContextFromContext(ctx context.Context) (string, string, bool) {
+	tc, ok := ctx.Value(traceContextKey{}).(traceContext)
+	return tc.traceparent, tc.tracestate, ok && traceparentPattern.MatchString(tc.traceparent)
+}
+
+// WithTraceContextPropagation sets the traceparent and tracestate headers of every request from its
+// context (see ContextWithTraceContext and FromContext). They're applied after the request callback.
+func WithTraceContextPropagation() Option {
+	return func(c *Client) {
+		c.propagateTraceContext = true
+	}
+}
+
+func (c *Client) applyTraceContext(req *http.Request) {
+	if !c.propagateTraceContext {
+		return
+	}
+
+	traceparent, tracestate, ok := TraceContextFromContext(req.Context())
+	if !ok {
+		return
+	}
+	req.Header.Set("traceparent", traceparent)
+	if len(tracestate) > 0 {
+		req.Header.Set("tracestate", tracestate)
+	}
+}
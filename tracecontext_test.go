@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldPropagateTraceContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Traceparent", r.Header.Get("traceparent"))
+		w.Header().Set("X-Tracestate", r.Header.Get("tracestate"))
+	}))
+	defer ts.Close()
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := ContextWithTraceContext(context.Background(), traceparent, "congo=t61rcWkgMzE")
+
+	c := FromContext(ctx, WithTraceContextPropagation())
+	re, err := c.Get(ts.URL, func(r *http.Request) {
+		r.Header.Set("traceparent", "overwritten")
+	})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.Header, "X-Traceparent", traceparent)
+	assertHeader(t, re.Header, "X-Tracestate", "congo=t61rcWkgMzE")
+
+	re, _ = FromContext(ctx).Get(ts.URL, nil)
+	assertHeader(t, re.Header, "X-Traceparent", "")
+}
+
+func TestShouldIgnoreInvalidTraceparent(t *testing.T) {
+	ctx := ContextWithTraceContext(context.Background(), "not-a-traceparent", "")
+	if _, _, ok := TraceContextFromContext(ctx); ok {
+		t.Error("Expected invalid traceparent to be ignored")
+	}
+
+	if _, _, ok := TraceContextFromContext(context.Background()); ok {
+		t.Error("Expected no trace context")
+	}
+}
@@ -0,0 +1,20 @@
+package rest
+
+import "net/http"
+
+// WithTransport sends requests through t instead of the transport built from the dial, TLS and HTTP/2
+// options, which are then ignored.
+func WithTransport(t http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// WithHTTPClient sends requests through hc, e.g. one set up with a corporate proxy or instrumentation,
+// instead of building a client from the Client's options. Requests are still bound to the Client's timeout;
+// a CheckRedirect set on hc replaces WithForwardAuthOnRedirect.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.client = hc
+	}
+}
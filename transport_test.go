@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestShouldSendThroughTransport(t *testing.T) {
+	c := New(WithTransport(RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-Transport": {"custom"}},
+			Body:       ioutil.NopCloser(strings.NewReader(req.URL.Path)),
+		}, nil
+	})))
+
+	re, err := c.Get("http://api.example.com/users", JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.Header, "X-Transport", "custom")
+	assertBody(t, re.BodyString(), "/users")
+}
+
+func TestShouldSendThroughHTTPClient(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	var requests int
+	hc := &http.Client{Transport: RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	re, err := New(WithHTTPClient(hc)).Post(ts.URL, strings.NewReader("{}"), JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertBody(t, re.BodyString(), "{}")
+	if requests != 1 {
+		t.Errorf("Expected requests: [%v] got: [%v]", 1, requests)
+	}
+}
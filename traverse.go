@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Traversal follows named hypermedia links from a root resource, see Follow.
+type Traversal struct {
+	client *Client
+	root   string
+	rels   []string
+}
+
+// Follow starts a traversal at the root URL that follows the links named rels in turn, e.g.
+// c.Follow(root, "orders", "items").Get(cb) gets the items linked from the orders linked from root.
+// Links are read from the HAL _links of JSON bodies, or else from Link headers. Templated links can't be
+// followed, as there are no values for their variables.
+func (c *Client) Follow(root string, rels ...string) *Traversal {
+	return &Traversal{client: c, root: root, rels: rels}
+}
+
+// Follow returns the traversal following the links named rels after those of t.
+func (t *Traversal) Follow(rels ...string) *Traversal {
+	return &Traversal{client: t.client, root: t.root, rels: append(append([]string(nil), t.rels...), rels...)}
+}
+
+// URL gets the resources along the traversal and returns the URL its last link points to. requestCallback
+// is applied to every request.
+func (t *Traversal) URL(requestCallback func(r *http.Request)) (string, error) {
+	target, err := t.client.resolveBaseURL(t.root)
+	if err != nil {
+		return "", err
+	}
+
+	for _, rel := range t.rels {
+		re, err := t.client.Get(target, func(r *http.Request) {
+			r.Header.Set("Accept", "application/hal+json, application/json")
+			if requestCallback != nil {
+				requestCallback(r)
+			}
+		})
+		if err != nil {
+			return "", err
+		}
+		if re.StatusCode < http.StatusOK || re.StatusCode >= http.StatusMultipleChoices {
+			return "", fmt.Errorf("rest: following %q from %s failed with status %d", rel, target, re.StatusCode)
+		}
+
+		link, ok := halLink(re.Body, rel)
+		if !ok {
+			link.Href, ok = linkRelations(re.Header)[rel]
+		}
+		if !ok {
+			return "", fmt.Errorf("rest: %s has no %q link", target, rel)
+		}
+		// a RFC 6570 template needs variables the traversal doesn't have, resolving it would keep its braces
+		if link.Templated || strings.ContainsAny(link.Href, "{}") {
+			return "", fmt.Errorf("rest: %q link of %s is the URI template %s", rel, target, link.Href)
+		}
+		if target, err = resolveReference(target, link.Href); err != nil {
+			return "", err
+		}
+	}
+	return target, nil
+}
+
+// Get gets the resource at the end of the traversal
+func (t *Traversal) Get(requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return t.Exchange(http.MethodGet, nil, requestCallback)
+}
+
+// Post posts body content to the resource at the end of the traversal
+func (t *Traversal) Post(body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	return t.Exchange(http.MethodPost, body, requestCallback)
+}
+
+// Exchange exchanges with the resource at the end of the traversal
+func (t *Traversal) Exchange(method string, body io.Reader, requestCallback func(r *http.Request)) (ResponseEntity, error) {
+	target, err := t.URL(requestCallback)
+	if err != nil {
+		return ResponseEntity{Header: make(http.Header)}, err
+	}
+	return t.client.Exchange(target, method, body, requestCallback)
+}
+
+// halLink returns the first rel link in the HAL _links of the body.
+func halLink(body []byte, rel string) (HALLink, bool) {
+	d, err := ParseHAL(body)
+	if err != nil {
+		return HALLink{}, false
+	}
+	link, ok := d.Link(rel)
+	return link, ok && len(link.Href) > 0
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// hypermediaTestServer links root to orders with HAL, orders to items with several HAL links and items
+// to a summary with a Link header.
+func hypermediaTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/hal+json")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`{"_links":{"self":{"href":"/"},"orders":{"href":"/orders"},"find":{"href":"/orders{?id}","templated":true}}}`))
+		case "/orders":
+			w.Write([]byte(`{"_links":{"items":[{"href":"orders/1/items"},{"href":"orders/2/items"}]}}`))
+		case "/orders/1/items":
+			w.Header().Set("Link", `</orders/1/items/summary>; rel="summary"`)
+			w.Write([]byte(`{"items":[]}`))
+		case "/orders/1/items/summary":
+			w.Write([]byte(r.Method + " summary"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestShouldFollowLinks(t *testing.T) {
+	ts := hypermediaTestServer()
+	defer ts.Close()
+
+	auth := func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer token")
+	}
+	c := New(WithBaseURL(ts.URL))
+
+	target, err := c.Follow("/", "orders", "items").URL(auth)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, target, ts.URL+"/orders/1/items")
+
+	re, err := c.Follow("/", "orders").Follow("items", "summary").Get(auth)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertBody(t, re.BodyString(), "GET summary")
+
+	re, err = c.Follow("/", "orders", "items", "summary").Post(strings.NewReader("{}"), auth)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertBody(t, re.BodyString(), "POST summary")
+}
+
+func TestShouldFailFollowingMissingLink(t *testing.T) {
+	ts := hypermediaTestServer()
+	defer ts.Close()
+
+	auth := func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer token")
+	}
+	if _, err := New().Follow(ts.URL, "customers").Get(auth); err == nil || !strings.Contains(err.Error(), `"customers"`) {
+		t.Errorf("Expected missing link error got: [%v]", err)
+	}
+	if _, err := New().Follow(ts.URL, "find").Get(auth); err == nil || !strings.Contains(err.Error(), "URI template /orders{?id}") {
+		t.Errorf("Expected URI template error got: [%v]", err)
+	}
+	if _, err := New().Follow(ts.URL, "orders").Get(nil); err == nil {
+		t.Error("Expected a status error")
+	}
+}
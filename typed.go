@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// GetJSON gets the content from the given URL and decodes its JSON body into a T.
+// A 204 or an empty 2xx body yields the zero T.
+func GetJSON[T any](c *Client, url string, requestCallback func(r *http.Request)) (T, ResponseEntity, error) {
+	var v T
+	re, err := c.GetJSON(url, &v, requestCallback)
+	return v, re, err
+}
+
+// PostJSON posts the JSON encoding of body to the given URL and decodes the JSON response into a T.
+func PostJSON[T any](c *Client, url string, body interface{}, requestCallback func(r *http.Request)) (T, ResponseEntity, error) {
+	return exchangeJSON[T](c, url, http.MethodPost, body, requestCallback)
+}
+
+// PutJSON puts the JSON encoding of body to the given URL and decodes the JSON response into a T.
+func PutJSON[T any](c *Client, url string, body interface{}, requestCallback func(r *http.Request)) (T, ResponseEntity, error) {
+	return exchangeJSON[T](c, url, http.MethodPut, body, requestCallback)
+}
+
+// PatchJSON patches the given URL with the JSON encoding of body and decodes the JSON response into a T.
+func PatchJSON[T any](c *Client, url string, body interface{}, requestCallback func(r *http.Request)) (T, ResponseEntity, error) {
+	return exchangeJSON[T](c, url, http.MethodPatch, body, requestCallback)
+}
+
+func exchangeJSON[T any](c *Client, url, method string, body interface{}, requestCallback func(r *http.Request)) (T, ResponseEntity, error) {
+	var v T
+	b, err := json.Marshal(body)
+	if err != nil {
+		return v, ResponseEntity{Header: make(http.Header)}, err
+	}
+
+	re, err := c.Exchange(url, method, bytes.NewReader(b), func(r *http.Request) {
+		if requestCallback != nil {
+			requestCallback(r)
+		}
+		r.Header.Set("Content-Type", "application/json")
+	})
+	if err != nil {
+		return v, re, err
+	}
+	return v, re, re.DecodeWith(DecodeJSON, &v)
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type typedTestPayload struct {
+	SomeProperty string `json:"someProperty"`
+}
+
+func TestShouldGetJSONTyped(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"someProperty\":\"someValue\"}"))
+	}))
+	defer ts.Close()
+
+	v, re, err := GetJSON[typedTestPayload](New(), ts.URL, JSONRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	if v.SomeProperty != "someValue" {
+		t.Errorf("Expected property: [%v] got: [%v]", "someValue", v.SomeProperty)
+	}
+}
+
+func TestShouldPostAndPutJSONTyped(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	v, re, err := PostJSON[typedTestPayload](New(), ts.URL, typedTestPayload{SomeProperty: "posted"}, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, re.Header, "Content-Type", "application/json")
+	if v.SomeProperty != "posted" {
+		t.Errorf("Expected property: [%v] got: [%v]", "posted", v.SomeProperty)
+	}
+
+	m, _, err := PutJSON[map[string]string](New(), ts.URL, typedTestPayload{SomeProperty: "put"}, nil)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if m["someProperty"] != "put" {
+		t.Errorf("Expected property: [%v] got: [%v]", "put", m["someProperty"])
+	}
+}
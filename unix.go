@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"context"
+	"net"
+)
+
+// WithUnixSocket connects to the Unix domain socket at path whatever the request host, so local daemons
+// such as Docker can be called with URLs like "http://unix/containers/json".
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		c.unixSocket = path
+	}
+}
+
+func dialUnix(dial dialFunc, path string) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dial(ctx, "unix", path)
+	}
+}
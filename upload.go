@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+)
+
+// UploadProgressCallback returns a request callback that applies requestCallback, if any, and then calls fn
+// as the request body is sent, with the bytes sent so far out of the Content-Length, -1 when unknown.
+// Replayed bodies, e.g. on retries, report their progress from zero again.
+func UploadProgressCallback(fn func(sent, total int64), requestCallback func(r *http.Request)) func(r *http.Request) {
+	return func(r *http.Request) {
+		if requestCallback != nil {
+			requestCallback(r)
+		}
+		if r.Body == nil || r.Body == http.NoBody {
+			return
+		}
+
+		total := r.ContentLength
+		if total == 0 {
+			total = -1
+		}
+		r.Body = &progressReadCloser{progressReader: progressReader{r: r.Body, total: total, fn: fn}, closer: r.Body}
+		if getBody := r.GetBody; getBody != nil {
+			r.GetBody = func() (io.ReadCloser, error) {
+				body, err := getBody()
+				if err != nil {
+					return nil, err
+				}
+				return &progressReadCloser{progressReader: progressReader{r: body, total: total, fn: fn}, closer: body}, nil
+			}
+		}
+	}
+}
+
+type progressReadCloser struct {
+	progressReader
+	closer io.Closer
+}
+
+func (rc *progressReadCloser) Close() error {
+	return rc.closer.Close()
+}
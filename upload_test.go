@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestShouldReportUploadProgress(t *testing.T) {
+	ts := entityTestServer()
+	defer ts.Close()
+
+	payload := bytes.Repeat([]byte("x"), 3*megabyte)
+	var reports int
+	var sent, total int64
+	re, err := New().Put(ts.URL, bytes.NewReader(payload), UploadProgressCallback(func(s, t int64) {
+		reports++
+		sent, total = s, t
+	}, JSONRequestCallback))
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	assertHeader(t, re.Header, "Content-Type", "application/json")
+	if len(re.Body) != len(payload) {
+		t.Errorf("Expected echoed bytes: [%v] got: [%v]", len(payload), len(re.Body))
+	}
+	if reports < 2 || sent != int64(len(payload)) || total != int64(len(payload)) {
+		t.Errorf("Expected progress up to: [%v/%v] got: [%v/%v] in [%v] reports", len(payload), len(payload), sent, total, reports)
+	}
+}
+
+func TestShouldReportUploadProgressOfUnknownLength(t *testing.T) {
+	var sent, total int64
+	c := New(WithDryRun(func(r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+	}))
+	c.Post("http://api.example.com/artifacts", io.MultiReader(strings.NewReader("abc")), UploadProgressCallback(func(s, t int64) {
+		sent, total = s, t
+	}, nil))
+
+	if sent != 3 || total != -1 {
+		t.Errorf("Expected progress: [%v/%v] got: [%v/%v]", 3, -1, sent, total)
+	}
+}
@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+)
+
+// XMLRequestCallback sets the headers of a request exchanging application/xml.
+func XMLRequestCallback(r *http.Request) {
+	r.Header.Add("Accept", "application/xml")
+	r.Header.Add("Content-Type", "application/xml")
+	r.Header.Add("Cache-Control", "no-cache")
+}
+
+// EncodeXML returns the XML encoding of v in a reader
+func EncodeXML(v interface{}) io.Reader {
+	w := new(bytes.Buffer)
+	xml.NewEncoder(w).Encode(v)
+	return w
+}
+
+// DecodeXML decodes the XML encoded b into the value pointed to by v, with the same semantics as xml.Unmarshal.
+func DecodeXML(b []byte, v interface{}) error {
+	return xml.Unmarshal(b, v)
+}
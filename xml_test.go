@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type xmlUser struct {
+	XMLName xml.Name `xml:"user"`
+	ID      int      `xml:"id,attr"`
+	Name    string   `xml:"name"`
+}
+
+func TestShouldExchangeXML(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var u xmlUser
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := DecodeXML(b, &u); err != nil || r.Header.Get("Content-Type") != "application/xml" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		u.ID = 7
+		w.Header().Set("Content-Type", r.Header.Get("Accept"))
+		w.Write([]byte(xml.Header))
+		xml.NewEncoder(w).Encode(u)
+	}))
+	defer ts.Close()
+
+	re, err := New().Post(ts.URL, EncodeXML(xmlUser{Name: "jose"}), XMLRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertStatusCode(t, re.StatusCode, http.StatusOK)
+	assertHeader(t, re.Header, "Content-Type", "application/xml")
+
+	var u xmlUser
+	if err := DecodeXML(re.Body, &u); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if u.ID != 7 || u.Name != "jose" {
+		t.Errorf("Expected user: [7 jose] got: [%v %v]", u.ID, u.Name)
+	}
+}
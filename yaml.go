@@ -0,0 +1,24 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+)
+
+// YAMLRequestCallback sets the headers of a request exchanging application/yaml.
+func YAMLRequestCallback(r *http.Request) {
+	r.Header.Add("Accept", "application/yaml")
+	r.Header.Add("Content-Type", "application/yaml")
+	r.Header.Add("Cache-Control", "no-cache")
+}
+
+// EncodeYAML returns the YAML encoding of v in a reader, using the codec registered for application/yaml.
+func EncodeYAML(v interface{}) (io.Reader, error) {
+	return encodeCodec("application/yaml", v)
+}
+
+// DecodeYAML decodes the YAML encoded b into the value pointed to by v, using the codec registered for
+// application/yaml.
+func DecodeYAML(b []byte, v interface{}) error {
+	return decodeCodec("application/yaml", b, v)
+}
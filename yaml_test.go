@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShouldExchangeYAML(t *testing.T) {
+	// JSON is valid YAML, which is enough to test the wiring without a YAML dependency
+	registerTestCodec(t, "application/yaml", Codec{Marshal: json.Marshal, Unmarshal: json.Unmarshal})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Write(b)
+	}))
+	defer ts.Close()
+
+	body, err := EncodeYAML(map[string]string{"name": "jose"})
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	re, err := New().Put(ts.URL, body, YAMLRequestCallback)
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	assertHeader(t, re.Header, "Content-Type", "application/yaml")
+
+	var v map[string]string
+	if err := DecodeYAML(re.Body, &v); err != nil {
+		t.Errorf("Error: %v", err)
+	}
+	if v["name"] != "jose" {
+		t.Errorf("Expected name: [jose] got: [%v]", v["name"])
+	}
+}
+
+func TestShouldFailYAMLWithoutCodec(t *testing.T) {
+	if _, err := EncodeYAML(map[string]string{}); err == nil {
+		t.Error("Expected an error without a YAML codec")
+	}
+	if err := DecodeYAML([]byte("name: jose"), &map[string]string{}); err == nil {
+		t.Error("Expected an error without a YAML codec")
+	}
+}